@@ -0,0 +1,447 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+const nullNode = -1
+
+// AABBTree is a dynamic bounding volume hierarchy, adapted from b2DynamicTree of Box2D.
+//
+// Objects are stored as proxies with fattened boxes, so small movements don't change the tree.
+// The tree is kept balanced with rotations. Proxies are identified by the proxy ID returned
+// from CreateProxy(); the user ID passed to CreateProxy() can be read back with ID().
+type AABBTree struct {
+	// Margin is added to each side of the proxy boxes.
+	Margin float64
+	// Multiplier scales the displacement used to enlarge the proxy boxes in MoveProxy().
+	Multiplier float64
+
+	nodes    []treeNode
+	root     int
+	freeList int
+	count    int
+}
+
+type treeNode struct {
+	box    AABB // Fattened box for leaves, union of children for branches
+	parent int  // Parent node, or next free node if the node is free
+	child1 int
+	child2 int
+	height int // 0 for leaves, -1 for free nodes
+	id     int // User ID of leaves
+	moved  bool
+}
+
+func (n *treeNode) isLeaf() bool { return n.child1 == nullNode }
+
+// NewAABBTree returns new AABBTree
+func NewAABBTree(margin float64) *AABBTree {
+	return &AABBTree{
+		Margin:     margin,
+		Multiplier: 2,
+		root:       nullNode,
+		freeList:   nullNode,
+	}
+}
+
+// Len returns the number of proxies in the tree.
+func (t *AABBTree) Len() int { return t.count }
+
+// Height returns the height of the tree. An empty tree has height -1.
+func (t *AABBTree) Height() int {
+	if t.root == nullNode {
+		return -1
+	}
+	return t.nodes[t.root].height
+}
+
+// CreateProxy inserts box with the user ID id into the tree and returns the proxy ID.
+func (t *AABBTree) CreateProxy(box *AABB, id int) int {
+	proxy := t.allocateNode()
+	node := &t.nodes[proxy]
+	node.box = AABB{Pos: box.Pos, Half: box.Half.Add(v.Vec{X: t.Margin, Y: t.Margin})}
+	node.id = id
+	node.height = 0
+	node.moved = true
+	t.insertLeaf(proxy)
+	t.count++
+	return proxy
+}
+
+// DestroyProxy removes the proxy from the tree.
+func (t *AABBTree) DestroyProxy(proxy int) {
+	t.removeLeaf(proxy)
+	t.freeNode(proxy)
+	t.count--
+}
+
+// MoveProxy updates the box of the proxy. displacement is the expected movement of the object
+// and is used to enlarge the fat box in the direction of travel.
+//
+// Returns true if the proxy was reinserted, false if box is still inside the fat box.
+func (t *AABBTree) MoveProxy(proxy int, box *AABB, displacement v.Vec) bool {
+	if BoxBoxContain(&t.nodes[proxy].box, box) {
+		return false
+	}
+	t.removeLeaf(proxy)
+
+	lo := box.Min().Sub(v.Vec{X: t.Margin, Y: t.Margin})
+	hi := box.Max().Add(v.Vec{X: t.Margin, Y: t.Margin})
+	d := displacement.Scale(t.Multiplier)
+	if d.X < 0 {
+		lo.X += d.X
+	} else {
+		hi.X += d.X
+	}
+	if d.Y < 0 {
+		lo.Y += d.Y
+	} else {
+		hi.Y += d.Y
+	}
+	t.nodes[proxy].box = boundsBox(lo, hi)
+	t.nodes[proxy].moved = true
+	t.insertLeaf(proxy)
+	return true
+}
+
+// FatAABB returns the fattened box of the proxy.
+func (t *AABBTree) FatAABB(proxy int) AABB { return t.nodes[proxy].box }
+
+// ID returns the user ID of the proxy.
+func (t *AABBTree) ID(proxy int) int { return t.nodes[proxy].id }
+
+// QueryAABB calls callback for each proxy whose fat box overlaps box.
+// Return false from callback to stop the query.
+func (t *AABBTree) QueryAABB(box *AABB, callback func(proxy int) bool) {
+	var buf [64]int
+	stack := append(buf[:0], t.root)
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if index == nullNode {
+			continue
+		}
+		node := &t.nodes[index]
+		if !BoxBoxOverlap(&node.box, box, nil) {
+			continue
+		}
+		if node.isLeaf() {
+			if !callback(index) {
+				break
+			}
+		} else {
+			stack = append(stack, node.child1, node.child2)
+		}
+	}
+}
+
+// RayCast calls callback for each proxy whose fat box is crossed by the ray from start to start+delta.
+//
+// callback receives the current maximum fraction (0.0 to 1.0) of delta and returns the new one:
+// return 0 to stop, maxFraction to continue unchanged or a smaller value to clip the ray.
+func (t *AABBTree) RayCast(start, delta v.Vec, callback func(proxy int, maxFraction float64) float64) {
+	if delta.IsZero() {
+		return
+	}
+	// separating axis perpendicular to the ray
+	axis := v.Vec{X: -delta.Y, Y: delta.X}.Unit()
+	absAxis := axis.Abs()
+	maxFraction := 1.0
+	segBox := boundsBox(
+		v.Vec{X: min(start.X, start.X+delta.X), Y: min(start.Y, start.Y+delta.Y)},
+		v.Vec{X: max(start.X, start.X+delta.X), Y: max(start.Y, start.Y+delta.Y)},
+	)
+
+	var buf [64]int
+	stack := append(buf[:0], t.root)
+	for len(stack) > 0 {
+		index := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if index == nullNode {
+			continue
+		}
+		node := &t.nodes[index]
+		if !boxesTouch(&node.box, &segBox) {
+			continue
+		}
+		if math.Abs(axis.Dot(start.Sub(node.box.Pos)))-absAxis.Dot(node.box.Half) > 0 {
+			continue
+		}
+		if !node.isLeaf() {
+			stack = append(stack, node.child1, node.child2)
+			continue
+		}
+		value := callback(index, maxFraction)
+		if value == 0 {
+			break
+		}
+		if value > 0 && value < maxFraction {
+			maxFraction = value
+			end := start.Add(delta.Scale(maxFraction))
+			segBox = boundsBox(
+				v.Vec{X: min(start.X, end.X), Y: min(start.Y, end.Y)},
+				v.Vec{X: max(start.X, end.X), Y: max(start.Y, end.Y)},
+			)
+		}
+	}
+}
+
+// Pairs appends all proxy pairs with overlapping fat boxes to dst and returns the extended slice.
+// Each pair is reported once, with the smaller proxy ID first.
+func (t *AABBTree) Pairs(dst [][2]int) [][2]int {
+	for i := range t.nodes {
+		if t.nodes[i].height != 0 {
+			continue
+		}
+		t.queryPairs(i, func(other int) bool { return other > i }, &dst)
+	}
+	return dst
+}
+
+// UpdatePairs appends the proxy pairs with overlapping fat boxes in which at least one proxy was
+// created or reinserted since the last call, and returns the extended slice.
+// Each pair is reported once, with the smaller proxy ID first.
+func (t *AABBTree) UpdatePairs(dst [][2]int) [][2]int {
+	for i := range t.nodes {
+		if t.nodes[i].height != 0 || !t.nodes[i].moved {
+			continue
+		}
+		// pairs of two moved proxies are reported by the proxy with the smaller ID
+		t.queryPairs(i, func(other int) bool { return !t.nodes[other].moved || other > i }, &dst)
+	}
+	for i := range t.nodes {
+		t.nodes[i].moved = false
+	}
+	return dst
+}
+
+// queryPairs appends the pairs of proxy with the overlapping proxies accepted by report
+func (t *AABBTree) queryPairs(proxy int, report func(other int) bool, dst *[][2]int) {
+	box := t.nodes[proxy].box
+	t.QueryAABB(&box, func(other int) bool {
+		if other != proxy && report(other) {
+			*dst = append(*dst, [2]int{min(proxy, other), max(proxy, other)})
+		}
+		return true
+	})
+}
+
+func (t *AABBTree) allocateNode() int {
+	if t.freeList == nullNode {
+		t.nodes = append(t.nodes, treeNode{})
+		t.freeList = len(t.nodes) - 1
+		t.nodes[t.freeList].parent = nullNode
+	}
+	index := t.freeList
+	t.freeList = t.nodes[index].parent
+	t.nodes[index] = treeNode{parent: nullNode, child1: nullNode, child2: nullNode}
+	return index
+}
+
+func (t *AABBTree) freeNode(index int) {
+	t.nodes[index] = treeNode{parent: t.freeList, child1: nullNode, child2: nullNode, height: -1}
+	t.freeList = index
+}
+
+func (t *AABBTree) insertLeaf(leaf int) {
+	if t.root == nullNode {
+		t.root = leaf
+		t.nodes[leaf].parent = nullNode
+		return
+	}
+
+	// find the best sibling
+	leafBox := t.nodes[leaf].box
+	index := t.root
+	for !t.nodes[index].isLeaf() {
+		node := &t.nodes[index]
+		area := boxPerimeter(&node.box)
+		combined := unionBox(&node.box, &leafBox)
+		combinedArea := boxPerimeter(&combined)
+
+		// cost of creating a new parent for this node and the new leaf
+		cost := 2 * combinedArea
+		// minimum cost of pushing the leaf further down the tree
+		inheritanceCost := 2 * (combinedArea - area)
+
+		cost1 := t.descendCost(node.child1, &leafBox) + inheritanceCost
+		cost2 := t.descendCost(node.child2, &leafBox) + inheritanceCost
+
+		if cost < cost1 && cost < cost2 {
+			break
+		}
+		if cost1 < cost2 {
+			index = node.child1
+		} else {
+			index = node.child2
+		}
+	}
+	sibling := index
+
+	// create a new parent
+	oldParent := t.nodes[sibling].parent
+	newParent := t.allocateNode()
+	t.nodes[newParent].parent = oldParent
+	t.nodes[newParent].box = unionBox(&leafBox, &t.nodes[sibling].box)
+	t.nodes[newParent].height = t.nodes[sibling].height + 1
+	t.nodes[newParent].child1 = sibling
+	t.nodes[newParent].child2 = leaf
+	t.nodes[sibling].parent = newParent
+	t.nodes[leaf].parent = newParent
+
+	if oldParent == nullNode {
+		t.root = newParent
+	} else if t.nodes[oldParent].child1 == sibling {
+		t.nodes[oldParent].child1 = newParent
+	} else {
+		t.nodes[oldParent].child2 = newParent
+	}
+
+	t.refit(t.nodes[leaf].parent)
+}
+
+// descendCost returns the cost of inserting box below child
+func (t *AABBTree) descendCost(child int, box *AABB) float64 {
+	u := unionBox(box, &t.nodes[child].box)
+	if t.nodes[child].isLeaf() {
+		return boxPerimeter(&u)
+	}
+	return boxPerimeter(&u) - boxPerimeter(&t.nodes[child].box)
+}
+
+func (t *AABBTree) removeLeaf(leaf int) {
+	if leaf == t.root {
+		t.root = nullNode
+		return
+	}
+
+	parent := t.nodes[leaf].parent
+	grandParent := t.nodes[parent].parent
+	sibling := t.nodes[parent].child1
+	if sibling == leaf {
+		sibling = t.nodes[parent].child2
+	}
+
+	if grandParent == nullNode {
+		t.root = sibling
+		t.nodes[sibling].parent = nullNode
+		t.freeNode(parent)
+		return
+	}
+
+	// destroy parent and connect sibling to grandParent
+	if t.nodes[grandParent].child1 == parent {
+		t.nodes[grandParent].child1 = sibling
+	} else {
+		t.nodes[grandParent].child2 = sibling
+	}
+	t.nodes[sibling].parent = grandParent
+	t.freeNode(parent)
+	t.refit(grandParent)
+}
+
+// refit walks up from index, balancing the tree and fixing heights and boxes
+func (t *AABBTree) refit(index int) {
+	for index != nullNode {
+		index = t.balance(index)
+		node := &t.nodes[index]
+		c1, c2 := &t.nodes[node.child1], &t.nodes[node.child2]
+		node.height = 1 + max(c1.height, c2.height)
+		node.box = unionBox(&c1.box, &c2.box)
+		index = node.parent
+	}
+}
+
+// balance performs a left or right rotation if node A is imbalanced and returns the new root index.
+func (t *AABBTree) balance(iA int) int {
+	A := &t.nodes[iA]
+	if A.isLeaf() || A.height < 2 {
+		return iA
+	}
+
+	iB, iC := A.child1, A.child2
+	B, C := &t.nodes[iB], &t.nodes[iC]
+	balance := C.height - B.height
+
+	// rotate C up
+	if balance > 1 {
+		return t.rotate(iA, iC, iB, false)
+	}
+	// rotate B up
+	if balance < -1 {
+		return t.rotate(iA, iB, iC, true)
+	}
+	return iA
+}
+
+// rotate moves child iUp of iA up one level. iOther is the other child of iA.
+// upIsChild1 tells which child slot of iA iUp occupies.
+func (t *AABBTree) rotate(iA, iUp, iOther int, upIsChild1 bool) int {
+	A := &t.nodes[iA]
+	U := &t.nodes[iUp]
+	O := &t.nodes[iOther]
+	iF, iG := U.child1, U.child2
+	F, G := &t.nodes[iF], &t.nodes[iG]
+
+	// swap A and U
+	U.child1 = iA
+	U.parent = A.parent
+	A.parent = iUp
+
+	// A's old parent should point to U
+	if U.parent != nullNode {
+		if t.nodes[U.parent].child1 == iA {
+			t.nodes[U.parent].child1 = iUp
+		} else {
+			t.nodes[U.parent].child2 = iUp
+		}
+	} else {
+		t.root = iUp
+	}
+
+	// the taller grandchild stays below U, the other one moves below A
+	keep, move := iF, iG
+	K, M := F, G
+	if F.height <= G.height {
+		keep, move = iG, iF
+		K, M = G, F
+	}
+	U.child2 = keep
+	if upIsChild1 {
+		A.child1 = move
+	} else {
+		A.child2 = move
+	}
+	M.parent = iA
+	A.box = unionBox(&O.box, &M.box)
+	U.box = unionBox(&A.box, &K.box)
+	A.height = 1 + max(O.height, M.height)
+	U.height = 1 + max(A.height, K.height)
+	return iUp
+}
+
+// unionBox returns the smallest box containing a and b
+func unionBox(a, b *AABB) AABB {
+	return boundsBox(
+		v.Vec{X: min(a.Left(), b.Left()), Y: min(a.Top(), b.Top())},
+		v.Vec{X: max(a.Right(), b.Right()), Y: max(a.Bottom(), b.Bottom())},
+	)
+}
+
+// boundsBox returns the box from the top-left corner lo to the bottom-right corner hi
+func boundsBox(lo, hi v.Vec) AABB {
+	return AABB{Pos: lo.Add(hi).Scale(0.5), Half: hi.Sub(lo).Scale(0.5)}
+}
+
+// boxPerimeter returns the perimeter of a, used as the cost metric of the tree
+func boxPerimeter(a *AABB) float64 {
+	return 4 * (a.Half.X + a.Half.Y)
+}
+
+// boxesTouch returns true if a and b overlap or touch
+func boxesTouch(a, b *AABB) bool {
+	return math.Abs(a.Pos.X-b.Pos.X) <= a.Half.X+b.Half.X && math.Abs(a.Pos.Y-b.Pos.Y) <= a.Half.Y+b.Half.Y
+}
@@ -0,0 +1,125 @@
+package coll
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/setanarut/v"
+)
+
+// randomBox returns a box on a half unit grid, so that many boxes touch exactly.
+// Some boxes have zero width or height.
+func randomBox(r *rand.Rand) AABB {
+	return AABB{
+		Pos:  v.Vec{X: float64(r.IntN(40)) / 2, Y: float64(r.IntN(40)) / 2},
+		Half: v.Vec{X: float64(r.IntN(5)) / 2, Y: float64(r.IntN(5)) / 2},
+	}
+}
+
+// bruteForcePairs returns the sorted ID pairs of the overlapping boxes, smaller ID first
+func bruteForcePairs(boxes map[int]AABB) [][2]int {
+	var pairs [][2]int
+	for a, boxA := range boxes {
+		for b, boxB := range boxes {
+			if a < b && BoxBoxOverlap(&boxA, &boxB, nil) {
+				pairs = append(pairs, [2]int{a, b})
+			}
+		}
+	}
+	slices.SortFunc(pairs, comparePairs)
+	return pairs
+}
+
+// sortedPairs sorts pairs and fails the test if a pair is reported twice
+func sortedPairs(t *testing.T, pairs [][2]int) [][2]int {
+	t.Helper()
+	pairs = slices.Clone(pairs)
+	slices.SortFunc(pairs, comparePairs)
+	for i := 1; i < len(pairs); i++ {
+		if pairs[i] == pairs[i-1] {
+			t.Fatalf("pair %v reported twice", pairs[i])
+		}
+	}
+	return pairs
+}
+
+func TestAABBTreeBruteForce(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	tree := NewAABBTree(0.25)
+	fat := map[int]AABB{} // fat boxes by proxy ID
+	moved := map[int]bool{}
+
+	check := func(step int) {
+		t.Helper()
+		// Pairs must not consume the moved flags needed by UpdatePairs
+		got := sortedPairs(t, tree.Pairs(nil))
+		if want := bruteForcePairs(fat); !slices.Equal(got, want) {
+			t.Fatalf("step %d: Pairs = %v, want %v", step, got, want)
+		}
+		var wantUpdated [][2]int
+		for _, p := range bruteForcePairs(fat) {
+			if moved[p[0]] || moved[p[1]] {
+				wantUpdated = append(wantUpdated, p)
+			}
+		}
+		got = sortedPairs(t, tree.UpdatePairs(nil))
+		if !slices.Equal(got, wantUpdated) {
+			t.Fatalf("step %d: UpdatePairs = %v, want %v", step, got, wantUpdated)
+		}
+		clear(moved)
+
+		query := randomBox(r)
+		var gotQuery []int
+		tree.QueryAABB(&query, func(proxy int) bool {
+			gotQuery = append(gotQuery, proxy)
+			return true
+		})
+		var wantQuery []int
+		for proxy, box := range fat {
+			if BoxBoxOverlap(&box, &query, nil) {
+				wantQuery = append(wantQuery, proxy)
+			}
+		}
+		slices.Sort(gotQuery)
+		slices.Sort(wantQuery)
+		if !slices.Equal(gotQuery, wantQuery) {
+			t.Fatalf("step %d: QueryAABB(%v) = %v, want %v", step, query, gotQuery, wantQuery)
+		}
+	}
+
+	for i := range 60 {
+		box := randomBox(r)
+		proxy := tree.CreateProxy(&box, i)
+		fat[proxy] = tree.FatAABB(proxy)
+		moved[proxy] = true
+	}
+	check(0)
+
+	for step := 1; step <= 20; step++ {
+		for _, proxy := range slices.Sorted(maps.Keys(fat)) {
+			switch r.IntN(6) {
+			case 0:
+				tree.DestroyProxy(proxy)
+				delete(fat, proxy)
+			case 1, 2:
+				box := randomBox(r)
+				if tree.MoveProxy(proxy, &box, v.Vec{}) {
+					moved[proxy] = true
+				}
+				fat[proxy] = tree.FatAABB(proxy)
+			}
+		}
+		for i := range 5 {
+			box := randomBox(r)
+			proxy := tree.CreateProxy(&box, step*100+i)
+			fat[proxy] = tree.FatAABB(proxy)
+			moved[proxy] = true
+		}
+		if tree.Len() != len(fat) {
+			t.Fatalf("step %d: Len = %d, want %d", step, tree.Len(), len(fat))
+		}
+		check(step)
+	}
+}
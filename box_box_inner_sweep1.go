@@ -62,3 +62,22 @@ func BoxBoxInnerSweep1(a, b *AABB, delta v.Vec, h *Hit) bool {
 	}
 	return false
 }
+
+// BoxBoxInnerSweep1Contact is like BoxBoxInnerSweep1(), but fills a Contact.
+// Point is on the edge of a at the time of impact, Feature is the inner edge of b that was hit.
+func BoxBoxInnerSweep1Contact(a, b *AABB, delta v.Vec, c *Contact) bool {
+	var h Hit
+	if !BoxBoxInnerSweep1(a, b, delta, &h) {
+		return false
+	}
+	if c != nil {
+		pos := a.Pos.Add(delta.Scale(h.Data))
+		*c = Contact{
+			Normal:  h.Normal,
+			Point:   pos.Sub(h.Normal.Mul(a.Half)),
+			Time:    h.Data,
+			Feature: boxEdge(h.Normal.Neg()),
+		}
+	}
+	return true
+}
@@ -48,3 +48,32 @@ func BoxBoxOverlap(a, b *AABB, h *Hit) bool {
 
 	return true
 }
+
+// BoxBoxOverlapContact is like BoxBoxOverlap(), but fills a Contact for box b.
+// Point is the center of the overlapping area on the edge of a, Feature is an edge or corner of a.
+func BoxBoxOverlapContact(a, b *AABB, c *Contact) bool {
+	var h Hit
+	if !BoxBoxOverlap(a, b, &h) {
+		return false
+	}
+	if c != nil {
+		*c = Contact{Normal: h.Normal, Depth: h.Data}
+		c.Point = boxBoxTouchPoint(a, b, h.Normal)
+		c.Feature = boxFeature(a, c.Point)
+	}
+	return true
+}
+
+// boxBoxTouchPoint returns the center of the range where b touches the edge of a with the given axis aligned normal
+func boxBoxTouchPoint(a, b *AABB, normal v.Vec) v.Vec {
+	if normal.X != 0 {
+		return v.Vec{
+			X: a.Pos.X + normal.X*a.Half.X,
+			Y: (max(a.Top(), b.Top()) + min(a.Bottom(), b.Bottom())) * 0.5,
+		}
+	}
+	return v.Vec{
+		X: (max(a.Left(), b.Left()) + min(a.Right(), b.Right())) * 0.5,
+		Y: a.Pos.Y + normal.Y*a.Half.Y,
+	}
+}
@@ -14,3 +14,40 @@ func BoxBoxSweep1(staticA, dynamicB *AABB, deltaB v.Vec, h *Hit) bool {
 	}
 	return result
 }
+
+// BoxBoxSweep1Contact is like BoxBoxSweep1(), but fills a Contact for dynamicB.
+// Point is on the edge of staticA at the time of impact, Feature is an edge or corner of staticA.
+func BoxBoxSweep1Contact(staticA, dynamicB *AABB, deltaB v.Vec, c *Contact) bool {
+	var h Hit
+	if !BoxBoxSweep1(staticA, dynamicB, deltaB, &h) {
+		return false
+	}
+	if c == nil {
+		return true
+	}
+	*c = Contact{Normal: h.Normal}
+	b := *dynamicB
+	if deltaB.IsZero() {
+		c.Depth = h.Data
+	} else {
+		c.Time = h.Data
+		b.Pos = b.Pos.Add(deltaB.Scale(h.Data))
+		var overlap Hit
+		if h.Data == 0 && BoxBoxOverlap(staticA, &b, &overlap) {
+			c.Depth = overlap.Data
+		}
+	}
+	c.Point = boxBoxTouchPoint(staticA, &b, h.Normal)
+	c.Feature = boxFeature(staticA, c.Point)
+	return true
+}
+
+// BoxBoxSweep1Interval returns true if dynamicB overlaps staticA during the movement
+// and fills iv with the time interval of the overlap if not nil.
+func BoxBoxSweep1Interval(staticA, dynamicB *AABB, deltaB v.Vec, iv *Interval) bool {
+	d := dynamicB.Pos.Sub(staticA.Pos)
+	hSum := staticA.Half.Add(dynamicB.Half)
+	enterX, exitX, okX := slabInterval(d.X, deltaB.X, -hSum.X, hSum.X)
+	enterY, exitY, okY := slabInterval(d.Y, deltaB.Y, -hSum.Y, hSum.Y)
+	return okX && okY && setInterval(max(enterX, enterY), min(exitX, exitY), iv)
+}
@@ -7,3 +7,21 @@ func BoxBoxSweep2(a, b *AABB, deltaA, deltaB v.Vec, h *Hit) bool {
 	delta := deltaB.Sub(deltaA)
 	return BoxBoxSweep1(a, b, delta, h)
 }
+
+// BoxBoxSweep2Contact is like BoxBoxSweep2(), but fills a Contact for b.
+// Point is on the edge of a at the time of impact, Feature is an edge or corner of a.
+func BoxBoxSweep2Contact(a, b *AABB, deltaA, deltaB v.Vec, c *Contact) bool {
+	if !BoxBoxSweep1Contact(a, b, deltaB.Sub(deltaA), c) {
+		return false
+	}
+	if c != nil {
+		c.Point = c.Point.Add(deltaA.Scale(c.Time))
+	}
+	return true
+}
+
+// BoxBoxSweep2Interval returns true if the moving boxes overlap during the movement
+// and fills iv with the time interval of the overlap if not nil.
+func BoxBoxSweep2Interval(a, b *AABB, deltaA, deltaB v.Vec, iv *Interval) bool {
+	return BoxBoxSweep1Interval(a, b, deltaB.Sub(deltaA), iv)
+}
@@ -19,11 +19,8 @@ func BoxCircleOverlap(a *AABB, c *Circle, h *Hit) bool {
 
 	// intersection test
 	diff := c.Pos.Sub(a.Pos)
-	clamped := v.Vec{
-		X: max(-a.Half.X, min(diff.X, a.Half.X)),
-		Y: max(-a.Half.Y, min(diff.Y, a.Half.Y)),
-	}
-	closest := a.Pos.Add(clamped)
+	closest := ClosestPointOnBox(a, c.Pos)
+	clamped := closest.Sub(a.Pos)
 	if !(c.Pos.DistSq(closest) <= c.Radius*c.Radius) {
 		return false
 	}
@@ -69,3 +66,36 @@ func BoxCircleOverlap(a *AABB, c *Circle, h *Hit) bool {
 	}
 	return true
 }
+
+// BoxCircleOverlapContact is like BoxCircleOverlap(), but fills a Contact.
+// Point is the point of the box closest to the circle center, Feature is an edge or corner of the box.
+func BoxCircleOverlapContact(a *AABB, c *Circle, ct *Contact) bool {
+	var h Hit
+	if !BoxCircleOverlap(a, c, &h) {
+		return false
+	}
+	if ct != nil {
+		*ct = Contact{Normal: h.Normal, Depth: h.Data}
+		ct.Point = boxClosestBoundaryPoint(a, c.Pos, h.Normal)
+		ct.Feature = boxFeature(a, ct.Point)
+	}
+	return true
+}
+
+// boxClosestBoundaryPoint returns the point on the boundary of a closest to p.
+// If p is inside the box, it is moved to the edge of the given normal.
+func boxClosestBoundaryPoint(a *AABB, p, normal v.Vec) v.Vec {
+	d := p.Sub(a.Pos)
+	clamped := v.Vec{
+		X: max(-a.Half.X, min(d.X, a.Half.X)),
+		Y: max(-a.Half.Y, min(d.Y, a.Half.Y)),
+	}
+	if clamped.Equals(d) {
+		if normal.X != 0 {
+			clamped.X = math.Copysign(a.Half.X, normal.X)
+		} else {
+			clamped.Y = math.Copysign(a.Half.Y, normal.Y)
+		}
+	}
+	return a.Pos.Add(clamped)
+}
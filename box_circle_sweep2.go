@@ -71,3 +71,53 @@ func BoxCircleSweep2(a *AABB, b *Circle, deltaA, deltaB v.Vec, h *Hit) bool {
 	h.Data = tmin
 	return true
 }
+
+// BoxCircleSweep2Contact is like BoxCircleSweep2(), but fills a Contact for the circle.
+// Point is the point of the box closest to the circle center at the time of impact,
+// Feature is an edge or corner of the box.
+func BoxCircleSweep2Contact(a *AABB, b *Circle, deltaA, deltaB v.Vec, c *Contact) bool {
+	var h Hit
+	if !BoxCircleSweep2(a, b, deltaA, deltaB, &h) {
+		return false
+	}
+	if c == nil {
+		return true
+	}
+	box := AABB{Pos: a.Pos.Add(deltaA.Scale(h.Data)), Half: a.Half}
+	circle := Circle{Pos: b.Pos.Add(deltaB.Scale(h.Data)), Radius: b.Radius}
+	*c = Contact{Normal: h.Normal, Time: h.Data}
+	var overlap Hit
+	if h.Data == 0 && BoxCircleOverlap(&box, &circle, &overlap) {
+		c.Depth = overlap.Data
+	}
+	c.Point = boxClosestBoundaryPoint(&box, circle.Pos, h.Normal)
+	c.Feature = boxFeature(&box, c.Point)
+	return true
+}
+
+// BoxCircleSweep2Interval returns true if the moving box and circle overlap during the movement
+// and fills iv with the time interval of the overlap if not nil.
+//
+// Unlike BoxCircleSweep2(), the corners of the box are rounded by the circle radius.
+func BoxCircleSweep2Interval(a *AABB, b *Circle, deltaA, deltaB v.Vec, iv *Interval) bool {
+	// move the circle center against the box grown by the radius, a union of two boxes and four circles
+	pos := b.Pos.Sub(a.Pos)
+	vel := deltaB.Sub(deltaA)
+	hx, hy, r := a.Half.X, a.Half.Y, b.Radius
+
+	enter, exit := math.Inf(1), math.Inf(-1)
+	add := func(e0, e1 float64, ok bool) {
+		if ok && e0 < e1 {
+			enter, exit = min(enter, e0), max(exit, e1)
+		}
+	}
+	for _, half := range [2]v.Vec{{X: hx + r, Y: hy}, {X: hx, Y: hy + r}} {
+		enterX, exitX, okX := slabInterval(pos.X, vel.X, -half.X, half.X)
+		enterY, exitY, okY := slabInterval(pos.Y, vel.Y, -half.Y, half.Y)
+		add(max(enterX, enterY), min(exitX, exitY), okX && okY)
+	}
+	for _, corner := range [4]v.Vec{{X: -hx, Y: -hy}, {X: hx, Y: -hy}, {X: hx, Y: hy}, {X: -hx, Y: hy}} {
+		add(circleInterval(pos, vel, corner, r))
+	}
+	return setInterval(enter, exit, iv)
+}
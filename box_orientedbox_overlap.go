@@ -39,3 +39,110 @@ func BoxOrientedBoxOverlap(a *AABB, o *OBB) bool {
 	projAOnObbY := bAxisYAbs.X*a.Half.X + bAxisYAbs.Y*a.Half.Y
 	return distOnObbY <= o.Half.Y+projAOnObbY
 }
+
+// BoxOrientedBoxOverlapContact tests an AABB and an OBB for overlap with the separating axis theorem
+// and fills a Contact for o if c is not nil.
+//
+//   - Normal: Collision surface normal for o (pointing from a to o)
+//   - Depth: Penetration depth along the normal
+//   - Point: Center of the deepest part of the overlap
+//   - Feature: The reference edge, 0-3 for the edges of a, 8-11 for the edges of o in its local frame
+func BoxOrientedBoxOverlapContact(a *AABB, o *OBB, c *Contact) bool {
+	return orientedBoxContact(&OBB{Pos: a.Pos, Half: a.Half}, o, c)
+}
+
+// orientedBoxAxes returns the local X and Y axes of o
+func orientedBoxAxes(o *OBB) [2]v.Vec {
+	x := v.FromAngle(o.Angle)
+	return [2]v.Vec{x, {X: -x.Y, Y: x.X}}
+}
+
+// orientedBoxRadius returns the half length of the projection of o onto axis
+func orientedBoxRadius(o *OBB, axes [2]v.Vec, axis v.Vec) float64 {
+	return o.Half.X*math.Abs(axes[0].Dot(axis)) + o.Half.Y*math.Abs(axes[1].Dot(axis))
+}
+
+// orientedBoxContact fills c for the oriented boxes a and b if they overlap.
+// The normal points from a to b.
+func orientedBoxContact(a, b *OBB, c *Contact) bool {
+	axesA, axesB := orientedBoxAxes(a), orientedBoxAxes(b)
+	d := b.Pos.Sub(a.Pos)
+
+	best := -1
+	var bestDepth float64
+	var normal v.Vec
+	for k, axis := range [4]v.Vec{axesA[0], axesA[1], axesB[0], axesB[1]} {
+		dist := d.Dot(axis)
+		depth := orientedBoxRadius(a, axesA, axis) + orientedBoxRadius(b, axesB, axis) - math.Abs(dist)
+		if depth < 0 {
+			return false
+		}
+		// prefer the axes of a to keep the reference edge stable
+		if best == -1 || (k < 2 && depth < bestDepth) || (k >= 2 && depth < bestDepth*0.98-Epsilon) {
+			best, bestDepth = k, depth
+			normal = axis
+			if dist < 0 {
+				normal = axis.Neg()
+			}
+		}
+	}
+	if c != nil {
+		*c = Contact{Normal: normal, Depth: bestDepth}
+		orientedBoxContactPoint(a, b, best, c)
+	}
+	return true
+}
+
+// orientedBoxContactPoint sets the Point and Feature of c for the boxes a and b touching
+// on the axis k (0, 1 for the axes of a, 2, 3 for the axes of b) with c.Normal pointing from a to b.
+func orientedBoxContactPoint(a, b *OBB, k int, c *Contact) {
+	ref, inc, refNormal := a, b, c.Normal
+	c.Feature = 0
+	if k >= 2 {
+		ref, inc, refNormal = b, a, c.Normal.Neg()
+		c.Feature = 8
+	}
+	refAxes, incAxes := orientedBoxAxes(ref), orientedBoxAxes(inc)
+	tangent := refAxes[1-k%2]
+	tangentHalf := ref.Half.X
+	if k%2 == 0 {
+		tangentHalf = ref.Half.Y
+	}
+
+	positive := refNormal.Dot(refAxes[k%2]) >= 0
+	switch {
+	case k%2 == 0 && positive:
+		c.Feature += FeatureRight
+	case k%2 == 0:
+		c.Feature += FeatureLeft
+	case positive:
+		c.Feature += FeatureBottom
+	default:
+		c.Feature += FeatureTop
+	}
+
+	// corners of the incident box
+	var corners [4]v.Vec
+	ex, ey := incAxes[0].Scale(inc.Half.X), incAxes[1].Scale(inc.Half.Y)
+	corners[0] = inc.Pos.Sub(ex).Sub(ey)
+	corners[1] = inc.Pos.Add(ex).Sub(ey)
+	corners[2] = inc.Pos.Add(ex).Add(ey)
+	corners[3] = inc.Pos.Sub(ex).Add(ey)
+
+	// average the deepest corners, clamped to the reference edge
+	deepest := math.Inf(1)
+	for _, p := range corners {
+		deepest = min(deepest, p.Dot(refNormal))
+	}
+	var sum v.Vec
+	n := 0.0
+	for _, p := range corners {
+		if p.Dot(refNormal) > deepest+Padding {
+			continue
+		}
+		t := p.Sub(ref.Pos).Dot(tangent)
+		sum = sum.Add(p.Add(tangent.Scale(max(-tangentHalf, min(t, tangentHalf)) - t)))
+		n++
+	}
+	c.Point = sum.Scale(1 / n)
+}
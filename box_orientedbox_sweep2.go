@@ -62,3 +62,83 @@ func BoxOrientedBoxSweep2(a *AABB, o *OBB, deltaA v.Vec, deltaO v.Vec) bool {
 
 	return true
 }
+
+// BoxOrientedBoxSweep2Contact finds the exact time of impact of a moving AABB and a moving OBB
+// with the separating axis theorem and fills a Contact for o if c is not nil.
+// Unlike BoxOrientedBoxSweep2(), it does not report hits of the swept volumes that don't
+// touch at the same time.
+//
+//   - Normal: Collision surface normal for o (pointing from a to o)
+//   - Time: Normalized time of impact (0.0 to 1.0) along the movement path
+//   - Depth: Penetration depth if the boxes already overlap at the start
+//   - Point, Feature: See BoxOrientedBoxOverlapContact()
+func BoxOrientedBoxSweep2Contact(a *AABB, o *OBB, deltaA v.Vec, deltaO v.Vec, c *Contact) bool {
+	box := OBB{Pos: a.Pos, Half: a.Half}
+	enter, exit, axisIndex, ok := orientedBoxSweep(&box, o, deltaO.Sub(deltaA))
+	if !ok || enter > 1 || exit < 0 {
+		return false
+	}
+	if enter <= 0 {
+		return orientedBoxContact(&box, o, c)
+	}
+	if c != nil {
+		box.Pos = box.Pos.Add(deltaA.Scale(enter))
+		moved := OBB{Pos: o.Pos.Add(deltaO.Scale(enter)), Half: o.Half, Angle: o.Angle}
+		axis := separatingAxes(&box, o)[axisIndex]
+		*c = Contact{Normal: axis, Time: enter}
+		if moved.Pos.Sub(box.Pos).Dot(axis) < 0 {
+			c.Normal = axis.Neg()
+		}
+		orientedBoxContactPoint(&box, &moved, axisIndex, c)
+	}
+	return true
+}
+
+// BoxOrientedBoxSweep2Interval returns true if the moving AABB and OBB overlap during the movement
+// and fills iv with the time interval of the overlap if not nil.
+func BoxOrientedBoxSweep2Interval(a *AABB, o *OBB, deltaA v.Vec, deltaO v.Vec, iv *Interval) bool {
+	return OrientedBoxOrientedBoxSweep2Interval(&OBB{Pos: a.Pos, Half: a.Half}, o, deltaA, deltaO, iv)
+}
+
+// OrientedBoxOrientedBoxSweep2Interval returns true if the moving OBBs overlap during the movement
+// and fills iv with the time interval of the overlap if not nil.
+func OrientedBoxOrientedBoxSweep2Interval(a, b *OBB, deltaA v.Vec, deltaB v.Vec, iv *Interval) bool {
+	enter, exit, _, ok := orientedBoxSweep(a, b, deltaB.Sub(deltaA))
+	return ok && setInterval(enter, exit, iv)
+}
+
+// separatingAxes returns the X and Y axes of a and b
+func separatingAxes(a, b *OBB) [4]v.Vec {
+	axesA, axesB := orientedBoxAxes(a), orientedBoxAxes(b)
+	return [4]v.Vec{axesA[0], axesA[1], axesB[0], axesB[1]}
+}
+
+// orientedBoxSweep returns the time range (not clamped) in which b, moving by rel relative to a,
+// overlaps a, and the index of the last separating axis crossed on entry (-1 if they always overlap).
+// Touching is not overlapping.
+func orientedBoxSweep(a, b *OBB, rel v.Vec) (enter, exit float64, axisIndex int, ok bool) {
+	axesA, axesB := orientedBoxAxes(a), orientedBoxAxes(b)
+	d := b.Pos.Sub(a.Pos)
+
+	enter, exit, axisIndex = math.Inf(-1), math.Inf(1), -1
+	for k, axis := range separatingAxes(a, b) {
+		dist := d.Dot(axis)
+		speed := rel.Dot(axis)
+		r := orientedBoxRadius(a, axesA, axis) + orientedBoxRadius(b, axesB, axis)
+		if math.Abs(speed) < Epsilon {
+			if math.Abs(dist) >= r {
+				return 0, 0, -1, false
+			}
+			continue
+		}
+		t1, t2 := (-r-dist)/speed, (r-dist)/speed
+		if lo := min(t1, t2); lo > enter {
+			enter, axisIndex = lo, k
+		}
+		exit = min(exit, max(t1, t2))
+		if enter > exit {
+			return 0, 0, -1, false
+		}
+	}
+	return enter, exit, axisIndex, true
+}
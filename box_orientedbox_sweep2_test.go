@@ -0,0 +1,42 @@
+package coll
+
+import (
+	"testing"
+
+	"github.com/setanarut/v"
+)
+
+func TestSweepIntervalsTouching(t *testing.T) {
+	a := NewAABB(0, 0, 1, 1)
+
+	tests := []struct {
+		name  string
+		b     *AABB
+		delta v.Vec
+		want  bool
+	}{
+		{"touching, sliding along the edge", NewAABB(2, 0, 1, 1), v.Vec{Y: 1}, false},
+		{"touching, standing still", NewAABB(2, 0, 1, 1), v.Vec{}, false},
+		{"touching, moving away", NewAABB(2, 0, 1, 1), v.Vec{X: 1}, false},
+		{"touching, moving into", NewAABB(2, 0, 1, 1), v.Vec{X: -1}, true},
+		{"overlapping, standing still", NewAABB(1.5, 0, 1, 1), v.Vec{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oa := OBB{Pos: a.Pos, Half: a.Half}
+			ob := OBB{Pos: tt.b.Pos, Half: tt.b.Half}
+			results := map[string]bool{
+				"BoxBoxSweep1Interval":                 BoxBoxSweep1Interval(a, tt.b, tt.delta, nil),
+				"BoxBoxSweep2Interval":                 BoxBoxSweep2Interval(a, tt.b, v.Vec{}, tt.delta, nil),
+				"BoxOrientedBoxSweep2Interval":         BoxOrientedBoxSweep2Interval(a, &ob, v.Vec{}, tt.delta, nil),
+				"OrientedBoxOrientedBoxSweep2Interval": OrientedBoxOrientedBoxSweep2Interval(&oa, &ob, v.Vec{}, tt.delta, nil),
+			}
+			for name, got := range results {
+				if got != tt.want {
+					t.Errorf("%s = %v, want %v", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
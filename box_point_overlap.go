@@ -36,3 +36,16 @@ func BoxPointOverlap(box *AABB, point v.Vec, h *Hit) bool {
 	}
 	return true
 }
+
+// BoxPointOverlapContact is like BoxPointOverlap(), but fills a Contact.
+// Point is the tested point, Feature is the edge of the box closest to it.
+func BoxPointOverlapContact(box *AABB, point v.Vec, c *Contact) bool {
+	var h Hit
+	if !BoxPointOverlap(box, point, &h) {
+		return false
+	}
+	if c != nil {
+		*c = Contact{Normal: h.Normal, Point: point, Depth: h.Data, Feature: boxEdge(h.Normal)}
+	}
+	return true
+}
@@ -54,3 +54,22 @@ func BoxSegmentOverlap(box *AABB, start, delta, padding v.Vec, h *Hit) bool {
 
 	return true
 }
+
+// BoxSegmentOverlapContact is like BoxSegmentOverlap(), but fills a Contact for the segment.
+// Time is the time along delta, Point is where the segment enters the padded box,
+// Feature is the edge of the box that was hit.
+func BoxSegmentOverlapContact(box *AABB, start, delta, padding v.Vec, c *Contact) bool {
+	var h Hit
+	if !BoxSegmentOverlap(box, start, delta, padding, &h) {
+		return false
+	}
+	if c != nil {
+		*c = Contact{
+			Normal:  h.Normal,
+			Point:   start.Add(delta.Scale(h.Data)),
+			Time:    h.Data,
+			Feature: boxEdge(h.Normal),
+		}
+	}
+	return true
+}
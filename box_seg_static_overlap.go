@@ -0,0 +1,59 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// BoxSegmentStaticOverlap checks whether box a and segment s overlap with the separating axis theorem.
+// Unlike BoxSegmentOverlap(), the segment is a static line segment, not a moving ray.
+// Touching is not overlapping.
+//
+// If oneSided is true, the segment only collides with a box whose center is on the side
+// its normal faces (see SegmentNormal()) and never pushes the box behind itself.
+//
+// If h is not nil, the function fills it with the minimum translation for box a:
+//   - Normal: Collision surface normal for box a
+//   - Data: the penetration depth for box a (overlap distance)
+//
+// To resolve the overlap:
+//
+//	newBoxPos = a.Pos.Add(hit.Normal.Scale(hit.Data))
+func BoxSegmentStaticOverlap(a *AABB, s *Segment, oneSided bool, h *Hit) bool {
+	lineNormal := SegmentNormal(s.A, s.B)
+	if oneSided && a.Pos.Sub(s.A).Dot(lineNormal) < 0 {
+		return false
+	}
+
+	var res Hit
+	best := math.Inf(1)
+	for _, axis := range [3]v.Vec{v.Right, v.Down, lineNormal} {
+		if axis.IsZero() {
+			continue
+		}
+		r := a.Half.X*math.Abs(axis.X) + a.Half.Y*math.Abs(axis.Y)
+		c := a.Pos.Dot(axis)
+		pa, pb := s.A.Dot(axis), s.B.Dot(axis)
+
+		// distances to push the box along the axis and against it
+		pos := max(pa, pb) - (c - r)
+		neg := (c + r) - min(pa, pb)
+		if pos <= 0 || neg <= 0 {
+			return false
+		}
+		if pos < best && !(oneSided && axis.Dot(lineNormal) < -Epsilon) {
+			best = pos
+			res = Hit{Normal: axis, Data: pos}
+		}
+		if neg < best && !(oneSided && axis.Dot(lineNormal) > Epsilon) {
+			best = neg
+			res = Hit{Normal: axis.Neg(), Data: neg}
+		}
+	}
+
+	if h != nil {
+		*h = res
+	}
+	return true
+}
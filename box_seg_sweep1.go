@@ -65,8 +65,13 @@ func BoxSegmentSweep1(s *Segment, a *AABB, deltaA v.Vec, h *Hit) bool {
 
 	// calculate first and last overlap times,
 	// as if we're dealing with a line rather than a segment
-	hitTime = max((boxProj-r)/dProj, hitTime)
-	outTime = min((boxProj+r)/dProj, outTime)
+	if dProj != 0 {
+		hitTime = max((boxProj-r)/dProj, hitTime)
+		outTime = min((boxProj+r)/dProj, outTime)
+	} else if math.Abs(boxProj) >= r {
+		// moving parallel to the line without crossing it
+		return false
+	}
 
 	// run standard AABBvsAABB sweep
 	// against an AABB constructed from the extents of the line segment
@@ -164,3 +169,81 @@ func BoxSegmentSweep1(s *Segment, a *AABB, deltaA v.Vec, h *Hit) bool {
 	}
 	return true
 }
+
+// BoxSegmentSweep1Contact is like BoxSegmentSweep1(), but fills a Contact for the box.
+// Point is the center of the part of the segment touching the box at the time of impact.
+// Feature is the face or an end point of the segment.
+func BoxSegmentSweep1Contact(s *Segment, a *AABB, deltaA v.Vec, c *Contact) bool {
+	var h Hit
+	if !BoxSegmentSweep1(s, a, deltaA, &h) {
+		return false
+	}
+	if c != nil {
+		boxSegmentContact(s, a, deltaA, &h, c)
+	}
+	return true
+}
+
+// boxSegmentContact fills c from the hit h of box a moving by deltaA against s
+func boxSegmentContact(s *Segment, a *AABB, deltaA v.Vec, h *Hit, c *Contact) {
+	box := AABB{Pos: a.Pos.Add(deltaA.Scale(h.Data)), Half: a.Half}
+	*c = Contact{Normal: h.Normal, Time: h.Data}
+
+	c.Point = segmentBoxMidpoint(s, &box)
+	if h.Normal != SegmentNormal(s.A, s.B) {
+		c.Feature, c.Point = FeatureEndA, s.A
+		if boxPointDistSq(box.Pos, box.Half, s.B) < boxPointDistSq(box.Pos, box.Half, s.A) {
+			c.Feature, c.Point = FeatureEndB, s.B
+		}
+	}
+
+	if h.Data == 0 {
+		// depth of the box corner deepest behind the surface
+		corner := box.Pos.Sub(v.Vec{X: math.Copysign(box.Half.X, h.Normal.X), Y: math.Copysign(box.Half.Y, h.Normal.Y)})
+		c.Depth = max(c.Point.Sub(corner).Dot(h.Normal), 0)
+	}
+}
+
+// segmentBoxMidpoint returns the center of the part of s inside box b grown by Padding.
+// If s misses the box, the point of s closest to the box center is returned.
+func segmentBoxMidpoint(s *Segment, b *AABB) v.Vec {
+	d := s.B.Sub(s.A)
+	t0, t1 := 0.0, 1.0
+	lo := b.Min().Sub(v.Vec{X: Padding, Y: Padding})
+	hi := b.Max().Add(v.Vec{X: Padding, Y: Padding})
+	for _, axis := range [2][4]float64{{s.A.X, d.X, lo.X, hi.X}, {s.A.Y, d.Y, lo.Y, hi.Y}} {
+		p, dp, amin, amax := axis[0], axis[1], axis[2], axis[3]
+		if dp == 0 {
+			continue
+		}
+		e0, e1 := (amin-p)/dp, (amax-p)/dp
+		t0 = max(t0, min(e0, e1))
+		t1 = min(t1, max(e0, e1))
+	}
+	if t0 <= t1 {
+		return s.A.Add(d.Scale((t0 + t1) * 0.5))
+	}
+	return s.A.Add(d.Scale(max(0, min(b.Pos.Sub(s.A).Dot(d)/d.MagSq(), 1))))
+}
+
+// BoxSegmentSweep1Interval returns true if the moving box overlaps the segment during the movement
+// and fills iv with the time interval of the overlap if not nil.
+//
+// Unlike BoxSegmentSweep1(), the segment is two-sided.
+func BoxSegmentSweep1Interval(s *Segment, a *AABB, deltaA v.Vec, iv *Interval) bool {
+	enter, exit := math.Inf(-1), math.Inf(1)
+	axes := [3]v.Vec{v.Right, v.Down, SegmentNormal(s.A, s.B)}
+	for _, axis := range axes {
+		if axis.IsZero() {
+			continue
+		}
+		r := a.Half.X*math.Abs(axis.X) + a.Half.Y*math.Abs(axis.Y)
+		pa, pb := s.A.Dot(axis), s.B.Dot(axis)
+		e0, e1, ok := slabInterval(a.Pos.Dot(axis), deltaA.Dot(axis), min(pa, pb)-r, max(pa, pb)+r)
+		if !ok {
+			return false
+		}
+		enter, exit = max(enter, e0), min(exit, e1)
+	}
+	return setInterval(enter, exit, iv)
+}
@@ -0,0 +1,103 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// BoxSegmentChainSweep1 returns the index of the colliding chain segment, or -1 if no collision was detected.
+//
+// It works like BoxSegmentsSweep1Indexed(), but knows about the neighbors of each segment.
+// Hits on an end point that is shared with the neighbor segment (ghost edges) are ignored
+// where the chain is flat. At a concave corner the hit is kept with the face normal of the segment,
+// at a convex corner only if the hit normal points outside of the corner.
+// This lets a box slide smoothly over collinear segments.
+//
+// If h is not nil and a collision is detected, it will be populated with:
+//   - Normal: Collision surface normal for the box
+//   - Data: Normalized time of impact (0.0 to 1.0) along the movement path
+func BoxSegmentChainSweep1(c *SegmentChain, a *AABB, deltaA v.Vec, h *Hit) (index int) {
+	colliderIndex := -1
+	var resHitTime float64
+	var tmpHitInfo Hit
+
+	for i := range c.Len() {
+		seg := c.Segment(i)
+		if !BoxSegmentSweep1(&seg, a, deltaA, &tmpHitInfo) {
+			continue
+		}
+		if colliderIndex != -1 && tmpHitInfo.Data >= resHitTime {
+			continue
+		}
+		if tmpHitInfo.Normal != SegmentNormal(seg.A, seg.B) && chainGhostHit(c, i, a, deltaA, &tmpHitInfo) {
+			continue
+		}
+		colliderIndex = i
+		resHitTime = tmpHitInfo.Data
+		if h != nil {
+			*h = tmpHitInfo
+		}
+	}
+	return colliderIndex
+}
+
+// chainGhostHit reports whether an end point hit on segment i is an internal edge of the chain.
+// At a concave corner the hit is kept and h.Normal is set to the face normal of segment i.
+func chainGhostHit(c *SegmentChain, i int, a *AABB, deltaA v.Vec, h *Hit) bool {
+	// find the end point touched by the box at the time of impact
+	pos := a.Pos.Add(deltaA.Scale(h.Data))
+	vertex := i
+	if boxPointDistSq(pos, a.Half, c.Points[(i+1)%len(c.Points)]) < boxPointDistSq(pos, a.Half, c.Points[i]) {
+		vertex = i + 1
+	}
+
+	n := len(c.Points)
+	if !c.Loop && (vertex == 0 || vertex == n-1) {
+		return false
+	}
+
+	prev := c.Points[(vertex-1+n)%n]
+	cur := c.Points[vertex%n]
+	next := c.Points[(vertex+1)%n]
+	d1 := cur.Sub(prev)
+	d2 := next.Sub(cur)
+
+	cross := d1.Cross(d2)
+	tolerance := Epsilon * d1.Mag() * d2.Mag()
+
+	// flat corner, the box slides over the shared end point
+	if math.Abs(cross) <= tolerance {
+		return true
+	}
+
+	// concave corner, the box can only touch the faces
+	if cross < 0 {
+		h.Normal = SegmentNormal(c.Points[i], c.Points[(i+1)%n])
+		return false
+	}
+
+	// convex corner, valid normals are between the normals of the two segments
+	n1 := SegmentNormal(prev, cur)
+	n2 := SegmentNormal(cur, next)
+	return n1.Cross(h.Normal) < -Epsilon || h.Normal.Cross(n2) < -Epsilon
+}
+
+// boxPointDistSq returns the squared distance between point p and the box at pos with half extents half
+func boxPointDistSq(pos, half, p v.Vec) float64 {
+	dx := max(math.Abs(p.X-pos.X)-half.X, 0)
+	dy := max(math.Abs(p.Y-pos.Y)-half.Y, 0)
+	return dx*dx + dy*dy
+}
+
+// BoxSegmentChainSweep1Contact is like BoxSegmentChainSweep1(), but fills a Contact for the box.
+// See BoxSegmentSweep1Contact().
+func BoxSegmentChainSweep1Contact(c *SegmentChain, a *AABB, deltaA v.Vec, ct *Contact) (index int) {
+	var h Hit
+	index = BoxSegmentChainSweep1(c, a, deltaA, &h)
+	if index != -1 && ct != nil {
+		seg := c.Segment(index)
+		boxSegmentContact(&seg, a, deltaA, &h, ct)
+	}
+	return index
+}
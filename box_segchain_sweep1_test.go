@@ -0,0 +1,79 @@
+package coll
+
+import (
+	"testing"
+
+	"github.com/setanarut/v"
+)
+
+func TestBoxSegmentChainSweep1(t *testing.T) {
+	upslope := NewSegmentChain(false, v.Vec{X: 0, Y: 0}, v.Vec{X: 10, Y: 0}, v.Vec{X: 20, Y: -10})
+	flat := NewSegmentChain(false, v.Vec{X: 0, Y: 0}, v.Vec{X: 10, Y: 0}, v.Vec{X: 20, Y: 0})
+	downslope := NewSegmentChain(false, v.Vec{X: 0, Y: 0}, v.Vec{X: 10, Y: 0}, v.Vec{X: 20, Y: 10})
+
+	tests := []struct {
+		name       string
+		chain      *SegmentChain
+		box        *AABB
+		delta      v.Vec
+		wantIndex  int
+		wantNormal v.Vec
+		wantTime   float64
+	}{
+		{
+			name:       "concave corner into upslope",
+			chain:      upslope,
+			box:        NewAABB(5, -1, 1, 1),
+			delta:      v.Vec{X: 8},
+			wantIndex:  1,
+			wantNormal: SegmentNormal(v.Vec{X: 10, Y: 0}, v.Vec{X: 20, Y: -10}),
+			wantTime:   0.5,
+		},
+		{
+			name:      "flat vertex is a ghost edge",
+			chain:     flat,
+			box:       NewAABB(5, -1, 1, 1),
+			delta:     v.Vec{X: 8},
+			wantIndex: -1,
+		},
+		{
+			name:      "convex corner over downslope",
+			chain:     downslope,
+			box:       NewAABB(5, -1, 1, 1),
+			delta:     v.Vec{X: 8},
+			wantIndex: -1,
+		},
+		{
+			name:       "falling onto flat chain",
+			chain:      flat,
+			box:        NewAABB(10, -5, 1, 1),
+			delta:      v.Vec{Y: 8},
+			wantIndex:  0,
+			wantNormal: v.Up,
+			wantTime:   0.5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var h Hit
+			index := BoxSegmentChainSweep1(tt.chain, tt.box, tt.delta, &h)
+			if index != tt.wantIndex {
+				t.Fatalf("index = %d, want %d", index, tt.wantIndex)
+			}
+			if index == -1 {
+				return
+			}
+			if !h.Normal.Equals(tt.wantNormal) {
+				t.Errorf("normal = %v, want %v", h.Normal, tt.wantNormal)
+			}
+			if !almostEqual(h.Data, tt.wantTime) {
+				t.Errorf("time = %v, want %v", h.Data, tt.wantTime)
+			}
+		})
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return a-b < 1e-6 && b-a < 1e-6
+}
@@ -0,0 +1,113 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// SurfaceType classifies a contact surface by the direction of its normal
+type SurfaceType uint8
+
+const (
+	SurfaceWall SurfaceType = iota
+	SurfaceFloor
+	SurfaceCeiling
+)
+
+// ClassifySurface returns the type of the surface with the given unit normal.
+//
+// Surfaces whose normal is within maxFloorAngle (radians) of v.Up are floors,
+// surfaces whose normal is within maxFloorAngle of v.Down are ceilings. All others are walls.
+func ClassifySurface(normal v.Vec, maxFloorAngle float64) SurfaceType {
+	minDot := math.Cos(maxFloorAngle) - Epsilon
+	up := normal.Dot(v.Up)
+	if up >= minDot {
+		return SurfaceFloor
+	}
+	if -up >= minDot {
+		return SurfaceCeiling
+	}
+	return SurfaceWall
+}
+
+// SlideContact is a contact found by a move and slide step
+type SlideContact struct {
+	Index   int         // Index of the segment
+	Hit     Hit         // Normal and time of impact (0.0 to 1.0) along the step
+	Surface SurfaceType // Type of the surface
+}
+
+// SlideResult holds the outcome of a move and slide
+type SlideResult struct {
+	Pos         v.Vec          // Final position of the box
+	Contacts    []SlideContact // Contacts in the order they happened
+	OnFloor     bool           // A floor was hit
+	OnWall      bool           // A wall was hit
+	OnCeiling   bool           // A ceiling was hit
+	FloorNormal v.Vec          // Normal of the last floor hit
+	Wedged      bool           // The box got stuck between two surfaces and stopped
+}
+
+// BoxSegmentsMoveAndSlide moves box a by delta against segs. When a segment is hit,
+// the box stops at the time of impact and the rest of the movement is projected onto
+// the surface and swept again, at most maxIterations times.
+//
+// If the remaining movement still points into the previous surface after sliding along the
+// current one, the box is wedged between two surfaces (like opposing walls or a narrow V) and stops.
+// Surfaces that just stop the movement, like a wall met while walking on a floor, are not wedging;
+// OnFloor and OnWall describe them.
+//
+// Contacts are classified with ClassifySurface() using maxFloorAngle (radians).
+// a is not modified; use the returned position.
+func BoxSegmentsMoveAndSlide(a *AABB, delta v.Vec, segs []*Segment, maxIterations int, maxFloorAngle float64) SlideResult {
+	var res SlideResult
+	var h Hit
+	box := *a
+	remaining := delta
+
+	for range maxIterations {
+		if remaining.MagSq() < Epsilon {
+			break
+		}
+		i := BoxSegmentsSweep1Indexed(segs, &box, remaining, &h)
+		if i < 0 {
+			box.Pos = box.Pos.Add(remaining)
+			remaining = v.Vec{}
+			break
+		}
+
+		// move to the time of impact and keep a small gap to the surface
+		box.Pos = box.Pos.Add(remaining.Scale(h.Data)).Add(h.Normal.Scale(Padding))
+
+		contact := SlideContact{Index: i, Hit: h, Surface: ClassifySurface(h.Normal, maxFloorAngle)}
+		res.Contacts = append(res.Contacts, contact)
+		switch contact.Surface {
+		case SurfaceFloor:
+			res.OnFloor = true
+			res.FloorNormal = h.Normal
+		case SurfaceCeiling:
+			res.OnCeiling = true
+		default:
+			res.OnWall = true
+		}
+
+		// project the rest of the movement onto the surface
+		remaining = remaining.Scale(1 - h.Data)
+		into := remaining.Dot(h.Normal)
+		if into < 0 {
+			remaining = remaining.Sub(h.Normal.Scale(into))
+		}
+
+		// the movement went into this surface and sliding along it pushes into the previous one
+		if n := len(res.Contacts); n > 1 {
+			prev := res.Contacts[n-2].Hit.Normal
+			if prev != h.Normal && into < -Epsilon && remaining.Dot(prev) < -Epsilon {
+				res.Wedged = true
+				break
+			}
+		}
+	}
+	res.Pos = box.Pos
+	return res
+}
@@ -0,0 +1,85 @@
+package coll
+
+import (
+	"math"
+	"testing"
+
+	"github.com/setanarut/v"
+)
+
+func TestBoxSegmentsMoveAndSlide(t *testing.T) {
+	floorAndWall := []*Segment{
+		NewSegment(-100, 0, 100, 0),
+		NewSegment(10, 0, 10, -100),
+	}
+	narrowV := []*Segment{
+		NewSegment(-10, -20, 0, 0),
+		NewSegment(0, 0, 10, -20),
+	}
+
+	tests := []struct {
+		name        string
+		segs        []*Segment
+		box         *AABB
+		delta       v.Vec
+		wantWedged  bool
+		wantFloor   bool
+		wantWall    bool
+		wantMaxStep float64 // largest allowed distance of the final position from wantPos
+		wantPos     v.Vec
+	}{
+		{
+			name:        "walk into wall on floor",
+			segs:        floorAndWall,
+			box:         NewAABB(5, -1.005, 1, 1),
+			delta:       v.Vec{X: 10, Y: 1},
+			wantFloor:   true,
+			wantWall:    true,
+			wantPos:     v.Vec{X: 9, Y: -1},
+			wantMaxStep: 2 * Padding,
+		},
+		{
+			name:        "slide along floor",
+			segs:        floorAndWall,
+			box:         NewAABB(-20, -1.005, 1, 1),
+			delta:       v.Vec{X: 10, Y: 1},
+			wantFloor:   true,
+			wantPos:     v.Vec{X: -10, Y: -1},
+			wantMaxStep: 2 * Padding,
+		},
+		{
+			name:        "fall into narrow V",
+			segs:        narrowV,
+			box:         NewAABB(0.5, -15, 1, 1),
+			delta:       v.Vec{Y: 20},
+			wantWedged:  true,
+			wantWall:    true,
+			wantPos:     v.Vec{X: 0, Y: -3},
+			wantMaxStep: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := BoxSegmentsMoveAndSlide(tt.box, tt.delta, tt.segs, 4, math.Pi/4)
+			if res.Wedged != tt.wantWedged {
+				t.Errorf("Wedged = %v, want %v", res.Wedged, tt.wantWedged)
+			}
+			if res.OnFloor != tt.wantFloor {
+				t.Errorf("OnFloor = %v, want %v", res.OnFloor, tt.wantFloor)
+			}
+			if res.OnWall != tt.wantWall {
+				t.Errorf("OnWall = %v, want %v", res.OnWall, tt.wantWall)
+			}
+			if d := res.Pos.Dist(tt.wantPos); d > tt.wantMaxStep {
+				t.Errorf("Pos = %v, want %v", res.Pos, tt.wantPos)
+			}
+			box := AABB{Pos: res.Pos, Half: tt.box.Half}
+			for i, s := range tt.segs {
+				if BoxSegmentStaticOverlap(&box, s, false, nil) {
+					t.Errorf("box overlaps segment %d at %v", i, res.Pos)
+				}
+			}
+		})
+	}
+}
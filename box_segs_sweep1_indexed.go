@@ -32,3 +32,14 @@ func BoxSegmentsSweep1Indexed(s []*Segment, a *AABB, deltaA v.Vec, h *Hit) (inde
 	}
 	return colliderIndex
 }
+
+// BoxSegmentsSweep1IndexedContact is like BoxSegmentsSweep1Indexed(), but fills a Contact for the box.
+// See BoxSegmentSweep1Contact().
+func BoxSegmentsSweep1IndexedContact(s []*Segment, a *AABB, deltaA v.Vec, c *Contact) (index int) {
+	var h Hit
+	index = BoxSegmentsSweep1Indexed(s, a, deltaA, &h)
+	if index != -1 && c != nil {
+		boxSegmentContact(s[index], a, deltaA, &h, c)
+	}
+	return index
+}
@@ -1,8 +1,10 @@
 package coll
 
 import (
+	"cmp"
 	"image"
 	"math"
+	"slices"
 
 	"github.com/setanarut/v"
 )
@@ -11,17 +13,69 @@ type Tile interface {
 	IsSolid() bool
 }
 
+// TriggerTile is implemented by non-solid tiles that should be reported
+// when a box overlaps or passes through them (ladders, water, spikes...).
+type TriggerTile interface {
+	TriggerKind() int
+}
+
+// TileMaterial holds the surface properties of a tile
+type TileMaterial struct {
+	Friction    float64 // Fraction of the tangential velocity lost on contact (0.0 to 1.0)
+	Restitution float64 // Bounciness (0.0 = no bounce, 1.0 = perfectly elastic)
+	Tag         int     // User defined tag (ice, mud, ...)
+}
+
+// MaterialTile is implemented by tiles that carry surface material data.
+type MaterialTile interface {
+	Material() TileMaterial
+}
+
 // TileHitInfo stores information about a collision with a tile
 type TileHitInfo struct {
+	TileCoords image.Point  // X,Y coordinates of the tile in the tilemap
+	Normal     v.Vec        // Normal vector of the collision (-1/0/1)
+	Material   TileMaterial // Material of the tile (zero value if the tile is not a MaterialTile)
+}
+
+// SlideVelocity returns vel with the component going into the surface removed.
+// The remaining tangential velocity is reduced by the material friction.
+func (h TileHitInfo) SlideVelocity(vel v.Vec) v.Vec {
+	normalSpeed := vel.Dot(h.Normal)
+	if normalSpeed >= 0 {
+		return vel
+	}
+	return vel.Sub(h.Normal.Scale(normalSpeed)).Scale(1 - h.Material.Friction)
+}
+
+// BounceVelocity returns vel reflected by the surface, scaled by the material restitution.
+// The tangential velocity is reduced by the material friction.
+func (h TileHitInfo) BounceVelocity(vel v.Vec) v.Vec {
+	normalSpeed := vel.Dot(h.Normal)
+	if normalSpeed >= 0 {
+		return vel
+	}
+	tangent := vel.Sub(h.Normal.Scale(normalSpeed))
+	return tangent.Scale(1 - h.Material.Friction).Sub(h.Normal.Scale(normalSpeed * h.Material.Restitution))
+}
+
+// TriggerHitInfo stores information about a trigger tile touched during movement
+type TriggerHitInfo struct {
 	TileCoords image.Point // X,Y coordinates of the tile in the tilemap
-	Normal     v.Vec       // Normal vector of the collision (-1/0/1)
+	Kind       int         // Value returned by TriggerKind()
+	Enter      float64     // Fraction of the path length (0.0 to 1.0) where the box enters the tile
+	Exit       float64     // Fraction of the path length (0.0 to 1.0) where the box leaves the tile
 }
 
 // TileCollider handles collision detection between AABB and [][]uint8 2D tilemap
 type TileCollider struct {
-	Collisions []TileHitInfo // List of collisions from last check
-	CellSize   image.Point   // Width and height of tiles
-	TileMap    [][]Tile      // 2D grid of tile interface
+	Collisions     []TileHitInfo    // List of collisions from last check
+	Triggers       []TriggerHitInfo // List of trigger tiles from last check, sorted by Enter
+	DetectTriggers bool             // If true, Collide fills Triggers
+	CellSize       image.Point      // Width and height of tiles
+	TileMap        [][]Tile         // 2D grid of tile interface
+
+	intervals []Interval // Buffer of Trace
 }
 
 // NewTileCollider creates a new tile collider with the given tilemap and tile dimensions
@@ -36,14 +90,24 @@ func NewTileCollider(tileMap [][]Tile, tileWidth, tileHeight int) *TileCollider
 type TileCollisionCallback func([]TileHitInfo, float64, float64)
 
 // Collide checks for collisions when a moving aabb and returns the allowed movement
+//
+// If DetectTriggers is true, non-solid tiles implementing TriggerTile that the box
+// overlaps along the allowed movement are stored in Triggers.
+// The path is the same as the movement: one axis first and then the other.
 func (c *TileCollider) Collide(box AABB, delta v.Vec, onCollide TileCollisionCallback) v.Vec {
 	c.Collisions = c.Collisions[:0]
+	c.Triggers = c.Triggers[:0]
+	start := box
 
 	if delta.X == 0 && delta.Y == 0 {
+		if c.DetectTriggers {
+			c.collectTriggers(&start, delta, true)
+		}
 		return delta
 	}
 
-	if math.Abs(delta.X) > math.Abs(delta.Y) {
+	xFirst := math.Abs(delta.X) > math.Abs(delta.Y)
+	if xFirst {
 		if delta.X != 0 {
 			delta.X = c.CollideX(&box, delta.X)
 		}
@@ -62,6 +126,10 @@ func (c *TileCollider) Collide(box AABB, delta v.Vec, onCollide TileCollisionCal
 		}
 	}
 
+	if c.DetectTriggers {
+		c.collectTriggers(&start, delta, xFirst)
+	}
+
 	if onCollide != nil {
 		onCollide(c.Collisions, delta.X, delta.Y)
 	}
@@ -97,10 +165,7 @@ func (c *TileCollider) CollideX(aabb *AABB, deltaX float64) float64 {
 					collision := tileLeft - (aabb.Pos.X + aabb.Half.X)
 					if collision <= deltaX {
 						deltaX = collision
-						c.Collisions = append(c.Collisions, TileHitInfo{
-							TileCoords: image.Point{x, y},
-							Normal:     v.Left,
-						})
+						c.Collisions = append(c.Collisions, c.hitInfo(x, y, v.Left))
 					}
 
 				}
@@ -128,10 +193,7 @@ func (c *TileCollider) CollideX(aabb *AABB, deltaX float64) float64 {
 					collision := tileRight - rectLeft
 					if collision >= deltaX {
 						deltaX = collision
-						c.Collisions = append(c.Collisions, TileHitInfo{
-							TileCoords: image.Point{x, y},
-							Normal:     v.Right,
-						})
+						c.Collisions = append(c.Collisions, c.hitInfo(x, y, v.Right))
 					}
 				}
 			}
@@ -171,10 +233,7 @@ func (c *TileCollider) CollideY(rect *AABB, deltaY float64) float64 {
 					collision := tileTop - rectBottom
 					if collision <= deltaY {
 						deltaY = collision
-						c.Collisions = append(c.Collisions, TileHitInfo{
-							TileCoords: image.Point{x, y},
-							Normal:     v.Up,
-						})
+						c.Collisions = append(c.Collisions, c.hitInfo(x, y, v.Up))
 					}
 				}
 			}
@@ -200,10 +259,7 @@ func (c *TileCollider) CollideY(rect *AABB, deltaY float64) float64 {
 					collision := tileBottom - rectTop
 					if collision >= deltaY {
 						deltaY = collision
-						c.Collisions = append(c.Collisions, TileHitInfo{
-							TileCoords: image.Point{x, y},
-							Normal:     v.Down,
-						})
+						c.Collisions = append(c.Collisions, c.hitInfo(x, y, v.Down))
 					}
 				}
 			}
@@ -211,3 +267,177 @@ func (c *TileCollider) CollideY(rect *AABB, deltaY float64) float64 {
 	}
 	return deltaY
 }
+
+// Trace moves box along delta in a straight line until it hits a solid tile.
+//
+// Unlike Collide(), the box does not slide along the tiles. Solid tiles that the box overlaps at
+// the start don't block it (StartSolid). If the box can't get out of the solid tiles during the
+// movement, AllSolid is set. Collisions is not changed.
+func (c *TileCollider) Trace(box AABB, delta v.Vec) Trace {
+	tr := Trace{Fraction: 1, EndPos: box.Pos.Add(delta)}
+	if len(c.TileMap) == 0 {
+		return tr
+	}
+	cellW := float64(c.CellSize.X)
+	cellH := float64(c.CellSize.Y)
+	end := AABB{Pos: tr.EndPos, Half: box.Half}
+	swept := unionBox(&box, &end)
+	minX := max(int(math.Floor(swept.Left()/cellW)), 0)
+	minY := max(int(math.Floor(swept.Top()/cellH)), 0)
+	maxX := min(int(math.Ceil(swept.Right()/cellW))-1, len(c.TileMap[0])-1)
+	maxY := min(int(math.Ceil(swept.Bottom()/cellH))-1, len(c.TileMap)-1)
+
+	c.intervals = c.intervals[:0]
+	enter := math.Inf(1)
+	var iv Interval
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			t := c.TileMap[y][x]
+			if t == nil || !t.IsSolid() {
+				continue
+			}
+			tile := AABB{
+				Pos:  v.Vec{X: (float64(x) + 0.5) * cellW, Y: (float64(y) + 0.5) * cellH},
+				Half: v.Vec{X: cellW / 2, Y: cellH / 2},
+			}
+			if !BoxBoxSweep1Interval(&tile, &box, delta, &iv) {
+				continue
+			}
+			c.intervals = append(c.intervals, iv)
+			if iv.StartOverlapping {
+				tr.StartSolid = true
+			} else if iv.Enter < enter {
+				enter = iv.Enter
+				tr.Normal = boxBoxNormal(&tile, &AABB{Pos: box.Pos.Add(delta.Scale(iv.Enter)), Half: box.Half})
+			}
+		}
+	}
+
+	if tr.StartSolid {
+		// the box is stuck if the solid tiles cover the whole movement
+		slices.SortFunc(c.intervals, func(a, b Interval) int {
+			return cmp.Compare(a.Enter, b.Enter)
+		})
+		covered := 0.0
+		for _, r := range c.intervals {
+			if r.Enter > covered {
+				break
+			}
+			covered = max(covered, r.Exit)
+		}
+		if covered >= 1 {
+			return Trace{EndPos: box.Pos, StartSolid: true, AllSolid: true}
+		}
+	}
+	if enter <= 1 {
+		tr.Fraction = max(enter-Epsilon, 0)
+		tr.EndPos = box.Pos.Add(delta.Scale(tr.Fraction))
+	}
+	return tr
+}
+
+// hitInfo returns the collision info for the tile at x, y
+func (c *TileCollider) hitInfo(x, y int, normal v.Vec) TileHitInfo {
+	info := TileHitInfo{TileCoords: image.Point{x, y}, Normal: normal}
+	if m, ok := c.TileMap[y][x].(MaterialTile); ok {
+		info.Material = m.Material()
+	}
+	return info
+}
+
+// collectTriggers appends the trigger tiles that box overlaps while moving by delta to Triggers.
+// The box moves along one axis and then the other like in Collide(), X first if xFirst is true.
+// Enter and Exit are fractions of the length of the whole path.
+func (c *TileCollider) collectTriggers(box *AABB, delta v.Vec, xFirst bool) {
+	moves := [2]v.Vec{{X: delta.X}, {Y: delta.Y}}
+	if !xFirst {
+		moves[0], moves[1] = moves[1], moves[0]
+	}
+	corner := *box
+	corner.Pos = corner.Pos.Add(moves[0])
+	// fraction of the path at the corner
+	split := 1.0
+	if length := math.Abs(delta.X) + math.Abs(delta.Y); length > 0 {
+		split = (math.Abs(moves[0].X) + math.Abs(moves[0].Y)) / length
+	}
+
+	cellW := float64(c.CellSize.X)
+	cellH := float64(c.CellSize.Y)
+
+	minX := min(box.Left(), box.Left()+delta.X)
+	maxX := max(box.Right(), box.Right()+delta.X)
+	minY := min(box.Top(), box.Top()+delta.Y)
+	maxY := max(box.Bottom(), box.Bottom()+delta.Y)
+
+	startX := max(int(math.Floor(minX/cellW)), 0)
+	endX := int(math.Ceil(maxX/cellW)) - 1
+	startY := max(int(math.Floor(minY/cellH)), 0)
+	endY := min(int(math.Ceil(maxY/cellH))-1, len(c.TileMap)-1)
+
+	for y := startY; y <= endY; y++ {
+		for x := startX; x <= min(endX, len(c.TileMap[y])-1); x++ {
+			tile := c.TileMap[y][x]
+			if tile == nil || tile.IsSolid() {
+				continue
+			}
+			trigger, ok := tile.(TriggerTile)
+			if !ok {
+				continue
+			}
+			cell := AABB{
+				Pos:  v.Vec{X: (float64(x) + 0.5) * cellW, Y: (float64(y) + 0.5) * cellH},
+				Half: v.Vec{X: cellW / 2, Y: cellH / 2},
+			}
+			enter, exit, ok := 2.0, -1.0, false
+			if split > 0 || delta.IsZero() {
+				if t0, t1, hit := boxBoxInterval(&cell, box, moves[0]); hit {
+					enter, exit, ok = t0*split, t1*split, true
+				}
+			}
+			if split < 1 {
+				if t0, t1, hit := boxBoxInterval(&cell, &corner, moves[1]); hit {
+					enter, exit, ok = min(enter, split+t0*(1-split)), split+t1*(1-split), true
+				}
+			}
+			if !ok {
+				continue
+			}
+			c.Triggers = append(c.Triggers, TriggerHitInfo{
+				TileCoords: image.Point{x, y},
+				Kind:       trigger.TriggerKind(),
+				Enter:      enter,
+				Exit:       exit,
+			})
+		}
+	}
+
+	slices.SortFunc(c.Triggers, func(a, b TriggerHitInfo) int {
+		return cmp.Compare(a.Enter, b.Enter)
+	})
+}
+
+// boxBoxInterval returns the time interval (clamped to 0.0 - 1.0) in which
+// box b, moving by delta, overlaps the static box a. Touching is not overlapping.
+func boxBoxInterval(a, b *AABB, delta v.Vec) (enter, exit float64, ok bool) {
+	enter, exit = 0, 1
+	d := b.Pos.Sub(a.Pos)
+	hSum := a.Half.Add(b.Half)
+
+	for _, axis := range [2][3]float64{{d.X, delta.X, hSum.X}, {d.Y, delta.Y, hSum.Y}} {
+		pos, vel, ext := axis[0], axis[1], axis[2]
+		if vel == 0 {
+			if math.Abs(pos) >= ext {
+				return 0, 0, false
+			}
+			continue
+		}
+		t1 := (-ext - pos) / vel
+		t2 := (ext - pos) / vel
+		enter = max(enter, min(t1, t2))
+		exit = min(exit, max(t1, t2))
+		if enter >= exit {
+			return 0, 0, false
+		}
+	}
+	return enter, exit, true
+}
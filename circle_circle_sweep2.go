@@ -47,3 +47,56 @@ func CircleCircleSweep2(c1, c2 *Circle, deltaC1, deltaC2 v.Vec, h *Hit) bool {
 
 	return false
 }
+
+// CircleCircleSweep2Contact is like CircleCircleSweep2(), but fills a Contact.
+// Point is on the surface of c2 at the time of impact, or halfway between the surfaces
+// if the circles already overlap.
+func CircleCircleSweep2Contact(c1, c2 *Circle, deltaC1, deltaC2 v.Vec, c *Contact) bool {
+	var h Hit
+	if !CircleCircleSweep2(c1, c2, deltaC1, deltaC2, &h) {
+		return false
+	}
+	if c != nil {
+		pos1 := c1.Pos.Add(deltaC1.Scale(h.Data))
+		pos2 := c2.Pos.Add(deltaC2.Scale(h.Data))
+		depth := max(c1.Radius+c2.Radius-pos1.Dist(pos2), 0)
+		if h.Data > 0 {
+			depth = 0
+		}
+		*c = Contact{
+			Normal: h.Normal,
+			Point:  pos2.Add(h.Normal.Scale(c2.Radius - depth*0.5)),
+			Time:   h.Data,
+			Depth:  depth,
+		}
+	}
+	return true
+}
+
+// CircleCircleSweep2Interval returns true if the moving circles overlap during the movement
+// and fills iv with the time interval of the overlap if not nil.
+func CircleCircleSweep2Interval(c1, c2 *Circle, deltaC1, deltaC2 v.Vec, iv *Interval) bool {
+	enter, exit, ok := circleInterval(c1.Pos, deltaC1.Sub(deltaC2), c2.Pos, c1.Radius+c2.Radius)
+	return ok && setInterval(enter, exit, iv)
+}
+
+// circleInterval returns the time range (not clamped) in which pos, moving by vel,
+// is inside the circle with the given center and radius.
+func circleInterval(pos, vel, center v.Vec, radius float64) (enter, exit float64, ok bool) {
+	m := pos.Sub(center)
+	c := m.MagSq() - radius*radius
+	a := vel.MagSq()
+	if a < Epsilon {
+		if c >= 0 {
+			return 0, 0, false
+		}
+		return math.Inf(-1), math.Inf(1), true
+	}
+	b := m.Dot(vel)
+	disc := b*b - a*c
+	if disc <= 0 {
+		return 0, 0, false
+	}
+	sqrtDisc := math.Sqrt(disc)
+	return (-b - sqrtDisc) / a, (-b + sqrtDisc) / a, true
+}
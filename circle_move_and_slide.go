@@ -0,0 +1,78 @@
+package coll
+
+import (
+	"github.com/setanarut/v"
+)
+
+// CircleMoveAndSlide moves circle c by vel against static boxes, segments and circles and
+// returns the final position and velocity of the circle. c is not modified.
+//
+// Each iteration the circle moves to the earliest hit, then the rest of the movement and the
+// velocity are reflected off the surface. A restitution of 0 slides along the surface, 1 bounces
+// without losing speed. Movement stops after maxIterations hits.
+//
+// Segments only block the circle from their front side (see CircleSegmentSweep1).
+// c itself is skipped if it is in circles.
+func CircleMoveAndSlide(c *Circle, vel v.Vec, boxes []*AABB, segs []*Segment, circles []*Circle, restitution float64, maxIterations int) (pos, newVel v.Vec) {
+	circle := *c
+	remaining := vel
+	newVel = vel
+
+	for range maxIterations {
+		if remaining.MagSq() < Epsilon {
+			break
+		}
+		h, ok := circleSweepAll(&circle, c, remaining, boxes, segs, circles)
+		if !ok {
+			circle.Pos = circle.Pos.Add(remaining)
+			remaining = v.Vec{}
+			break
+		}
+
+		// move to the time of impact and keep a small gap to the surface
+		circle.Pos = circle.Pos.Add(remaining.Scale(h.Data)).Add(h.Normal.Scale(Padding))
+
+		remaining = reflectVelocity(remaining.Scale(1-h.Data), h.Normal, restitution)
+		newVel = reflectVelocity(newVel, h.Normal, restitution)
+	}
+	return circle.Pos, newVel
+}
+
+// circleSweepAll returns the earliest hit of circle c moving by delta against all shapes.
+// Hits that delta moves away from are ignored. self is skipped in circles.
+func circleSweepAll(c, self *Circle, delta v.Vec, boxes []*AABB, segs []*Segment, circles []*Circle) (Hit, bool) {
+	var res, tmp Hit
+	found := false
+	keep := func() {
+		if tmp.Normal.Dot(delta) < 0 && (!found || tmp.Data < res.Data) {
+			res = tmp
+			found = true
+		}
+	}
+	for _, b := range boxes {
+		if BoxCircleSweep2(b, c, v.Vec{}, delta, &tmp) {
+			keep()
+		}
+	}
+	for _, s := range segs {
+		if CircleSegmentSweep1(s, c, delta, &tmp) {
+			keep()
+		}
+	}
+	for _, other := range circles {
+		if other != self && CircleCircleSweep2(c, other, delta, v.Vec{}, &tmp) {
+			keep()
+		}
+	}
+	return res, found
+}
+
+// reflectVelocity removes the part of vel going into the surface with the given normal
+// and adds it back scaled by restitution in the opposite direction.
+func reflectVelocity(vel, normal v.Vec, restitution float64) v.Vec {
+	into := vel.Dot(normal)
+	if into >= 0 {
+		return vel
+	}
+	return vel.Sub(normal.Scale((1 + restitution) * into))
+}
@@ -37,3 +37,16 @@ func CirclePointOverlap(circle *Circle, point v.Vec, h *Hit) bool {
 	}
 	return true
 }
+
+// CirclePointOverlapContact is like CirclePointOverlap(), but fills a Contact.
+// Point is the tested point.
+func CirclePointOverlapContact(circle *Circle, point v.Vec, c *Contact) bool {
+	var h Hit
+	if !CirclePointOverlap(circle, point, &h) {
+		return false
+	}
+	if c != nil {
+		*c = Contact{Normal: h.Normal, Point: point, Depth: h.Data}
+	}
+	return true
+}
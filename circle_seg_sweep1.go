@@ -0,0 +1,85 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// CircleSegmentSweep1 sweep a moving circle against a static line segment.
+//
+// Like BoxSegmentSweep1(), the segment only blocks the circle from the side its normal faces.
+// Hits on the rounded ends of the segment are reported with the normal pointing from the end point
+// to the circle center.
+//
+// If h is not nil and a collision is detected, it will be populated with:
+//   - Normal: Collision surface normal for the circle
+//   - Data: Normalized time of impact (0.0 to 1.0) along the movement path
+func CircleSegmentSweep1(s *Segment, c *Circle, deltaC v.Vec, h *Hit) bool {
+	lineDir := s.B.Sub(s.A)
+	lineLenSq := lineDir.MagSq()
+	lineNormal := SegmentNormal(s.A, s.B)
+
+	hit := false
+	var res, tmp Hit
+
+	// face of the segment
+	dist := c.Pos.Sub(s.A).Dot(lineNormal)
+	dProj := deltaC.Dot(lineNormal)
+	if lineLenSq > Epsilon && dist >= 0 && dProj < 0 {
+		t := max((dist-c.Radius)/-dProj, 0)
+		if t <= 1 {
+			u := c.Pos.Add(deltaC.Scale(t)).Sub(s.A).Dot(lineDir) / lineLenSq
+			if u >= 0 && u <= 1 {
+				hit = true
+				res = Hit{Normal: lineNormal, Data: t}
+			}
+		}
+	}
+
+	// rounded ends of the segment
+	for _, p := range [2]v.Vec{s.A, s.B} {
+		end := Circle{Pos: p, Radius: c.Radius}
+		if !rayCircle(c.Pos, deltaC, &end, &tmp) {
+			continue
+		}
+		if tmp.Normal.Dot(deltaC) >= 0 || tmp.Normal.Dot(lineNormal) < 0 {
+			continue
+		}
+		if !hit || tmp.Data < res.Data {
+			hit = true
+			res = tmp
+		}
+	}
+
+	if hit && h != nil {
+		*h = res
+	}
+	return hit
+}
+
+// CircleSegmentSweep1Contact is like CircleSegmentSweep1(), but fills a Contact for the circle.
+// Point is on the segment at the time of impact, Feature is the face or an end point of the segment.
+func CircleSegmentSweep1Contact(s *Segment, c *Circle, deltaC v.Vec, ct *Contact) bool {
+	var h Hit
+	if !CircleSegmentSweep1(s, c, deltaC, &h) {
+		return false
+	}
+	if ct == nil {
+		return true
+	}
+	pos := c.Pos.Add(deltaC.Scale(h.Data))
+	*ct = Contact{Normal: h.Normal, Point: pos.Sub(h.Normal.Scale(c.Radius)), Time: h.Data}
+	if h.Normal != SegmentNormal(s.A, s.B) {
+		ct.Feature = FeatureEndA
+		ct.Point = s.A
+		if pos.DistSq(s.B) < pos.DistSq(s.A) {
+			ct.Feature = FeatureEndB
+			ct.Point = s.B
+		}
+	}
+	if h.Data == 0 {
+		ct.Depth = max(c.Radius-math.Sqrt(segmentPointDistSq(s, pos)), 0)
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package coll
+
+import (
+	"github.com/setanarut/v"
+)
+
+// ClosestPointOnBox returns the point of box a closest to p.
+// If p is inside the box, p is returned.
+func ClosestPointOnBox(a *AABB, p v.Vec) v.Vec {
+	return v.Vec{
+		X: max(a.Left(), min(p.X, a.Right())),
+		Y: max(a.Top(), min(p.Y, a.Bottom())),
+	}
+}
+
+// ClosestPointOnCircle returns the point of circle c closest to p.
+// If p is inside the circle, p is returned.
+func ClosestPointOnCircle(c *Circle, p v.Vec) v.Vec {
+	d := p.Sub(c.Pos)
+	if d.MagSq() <= c.Radius*c.Radius {
+		return p
+	}
+	return c.Pos.Add(d.Unit().Scale(c.Radius))
+}
+
+// ClosestPointOnSegment returns the point of segment s closest to p.
+func ClosestPointOnSegment(s *Segment, p v.Vec) v.Vec {
+	ab := s.B.Sub(s.A)
+	lenSq := ab.MagSq()
+	if lenSq == 0 {
+		return s.A
+	}
+	t := max(0, min(1, p.Sub(s.A).Dot(ab)/lenSq))
+	return s.A.Add(ab.Scale(t))
+}
+
+// ClosestPointOnOrientedBox returns the point of oriented box o closest to p.
+// If p is inside the box, p is returned.
+func ClosestPointOnOrientedBox(o *OBB, p v.Vec) v.Vec {
+	local := p.Sub(o.Pos).Rotate(-o.Angle)
+	clamped := v.Vec{
+		X: max(-o.Half.X, min(local.X, o.Half.X)),
+		Y: max(-o.Half.Y, min(local.Y, o.Half.Y)),
+	}
+	if clamped == local {
+		return p
+	}
+	return o.Pos.Add(clamped.Rotate(o.Angle))
+}
+
+// ClosestPointOnPolygon returns the point of convex polygon poly closest to p.
+// If p is inside the polygon, p is returned.
+func ClosestPointOnPolygon(poly *Polygon, p v.Vec) v.Vec {
+	if len(poly.Points) == 0 {
+		return p
+	}
+	if pointInPolygon(poly.Points, p) {
+		return p
+	}
+	return closestPointOnEdges(poly.Points, p)
+}
+
+// pointInPolygon returns true if p is inside or on the convex polygon points.
+// Less than 3 points never contain p.
+func pointInPolygon(points []v.Vec, p v.Vec) bool {
+	if len(points) < 3 {
+		return false
+	}
+	for i, a := range points {
+		if p.Sub(a).Dot(SegmentNormal(a, points[(i+1)%len(points)])) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// closestPointOnEdges returns the point of the edges of the polygon points closest to p.
+// One point is a degenerate edge, two points are a single edge.
+func closestPointOnEdges(points []v.Vec, p v.Vec) v.Vec {
+	best, bestDistSq := points[0], p.DistSq(points[0])
+	for i, a := range points {
+		if len(points) == 2 && i == 1 {
+			break
+		}
+		q := ClosestPointOnSegment(&Segment{A: a, B: points[(i+1)%len(points)]}, p)
+		if d := p.DistSq(q); d < bestDistSq {
+			best, bestDistSq = q, d
+		}
+	}
+	return best
+}
@@ -2,6 +2,8 @@
 package coll
 
 import (
+	"math"
+
 	"github.com/setanarut/v"
 )
 
@@ -20,6 +22,56 @@ type Hit struct {
 	Data float64
 }
 
+// Contact holds the full result of an overlap or sweep test.
+//
+// Unlike Hit, the time of impact and the penetration depth are separate fields,
+// and the contact point is included. The ...Contact variants of the overlap and
+// sweep functions fill it with the same normal direction as their Hit versions.
+type Contact struct {
+	// The normal vector of the hit.
+	Normal v.Vec
+	// Contact point in world space. For sweeps, the point at the time of impact.
+	Point v.Vec
+	// The time (0.0 to 1.0) along the movement path. 0 for overlap tests.
+	Time float64
+	// Penetration depth. 0 for sweeps, unless the shapes already overlap at the start.
+	Depth float64
+	// Touched feature of the surface the normal belongs to.
+	//
+	// Boxes: 0-3 the left, top, right and bottom edges, 4-7 the top-left, top-right,
+	// bottom-right and bottom-left corners. Segments: 0 the face, 1 and 2 the end points A and B.
+	// Circles and points: 0. See the function docs for exceptions.
+	Feature int
+}
+
+// Box and segment features of Contact.Feature
+const (
+	FeatureLeft = iota
+	FeatureTop
+	FeatureRight
+	FeatureBottom
+	FeatureTopLeft
+	FeatureTopRight
+	FeatureBottomRight
+	FeatureBottomLeft
+)
+
+// Segment features of Contact.Feature
+const (
+	FeatureFace = iota
+	FeatureEndA
+	FeatureEndB
+)
+
+// Interval is the time range along the movement path in which two moving shapes overlap.
+// Times are clamped to the movement (0.0 to 1.0).
+type Interval struct {
+	Enter float64 // Time when the shapes start to overlap
+	Exit  float64 // Time when the shapes stop overlapping (1.0 if they still overlap at the end)
+	// The shapes already overlap at the start of the movement (Enter is 0)
+	StartOverlapping bool
+}
+
 // Resets the zero values.
 func (h *Hit) Reset() {
 	*h = Hit{} // Reinitializes all fields of the struct to their zero values (nil, 0, false, etc.).
@@ -33,3 +85,63 @@ func SegmentNormal(pointA, pointB v.Vec) (normal v.Vec) {
 	}
 	return v.Vec{X: d.Y, Y: -d.X}.Unit()
 }
+
+// boxEdge returns the edge feature of a box for an axis aligned normal
+func boxEdge(normal v.Vec) int {
+	switch {
+	case normal.X < 0:
+		return FeatureLeft
+	case normal.Y < 0:
+		return FeatureTop
+	case normal.X > 0:
+		return FeatureRight
+	}
+	return FeatureBottom
+}
+
+// boxFeature returns the edge or corner feature of box a nearest to the point p on its boundary
+func boxFeature(a *AABB, p v.Vec) int {
+	d := p.Sub(a.Pos)
+	gapX := a.Half.X - math.Abs(d.X)
+	gapY := a.Half.Y - math.Abs(d.Y)
+	if gapX <= Padding*0.5 && gapY <= Padding*0.5 {
+		switch {
+		case d.X < 0 && d.Y < 0:
+			return FeatureTopLeft
+		case d.Y < 0:
+			return FeatureTopRight
+		case d.X < 0:
+			return FeatureBottomLeft
+		}
+		return FeatureBottomRight
+	}
+	if gapX < gapY {
+		return boxEdge(v.Vec{X: d.X})
+	}
+	return boxEdge(v.Vec{Y: d.Y})
+}
+
+// setInterval clamps the overlap time range [enter, exit] to the movement and stores it in iv if not nil.
+// Returns false if the shapes don't overlap during the movement. Touching is not overlapping.
+func setInterval(enter, exit float64, iv *Interval) bool {
+	if exit <= 0 || enter >= 1 || enter >= exit {
+		return false
+	}
+	if iv != nil {
+		*iv = Interval{Enter: max(enter, 0), Exit: min(exit, 1), StartOverlapping: enter < 0}
+	}
+	return true
+}
+
+// slabInterval returns the time range in which pos, moving by vel, is inside [lo, hi].
+// The range is not clamped. Returns false if pos doesn't move and is outside.
+func slabInterval(pos, vel, lo, hi float64) (enter, exit float64, ok bool) {
+	if vel == 0 {
+		if pos <= lo || pos >= hi {
+			return 0, 0, false
+		}
+		return math.Inf(-1), math.Inf(1), true
+	}
+	t1, t2 := (lo-pos)/vel, (hi-pos)/vel
+	return min(t1, t2), max(t1, t2), true
+}
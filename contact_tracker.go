@@ -0,0 +1,109 @@
+package coll
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ContactEventType is the type of a ContactEvent
+type ContactEventType uint8
+
+const (
+	ContactBegin ContactEventType = iota // The pair started touching in this frame
+	ContactStay                          // The pair was already touching in the previous frame
+	ContactEnd                           // The pair stopped touching in this frame
+)
+
+// ContactEvent describes the contact state of a pair in the current frame.
+type ContactEvent struct {
+	Type ContactEventType
+	Key  uint64 // Stable pair ID, see PairKey()
+	A, B int    // IDs of the pair, in the order of the latest Add() call
+	// The latest hit info of the pair. For ContactEnd it is the hit of the last frame with contact.
+	Hit Hit
+	// At least one of the pair is a sensor. Sensor contacts generate events
+	// but should not be used for collision resolution.
+	Sensor bool
+}
+
+// PairKey returns a stable ID for the unordered pair of IDs a and b.
+// IDs must fit in 32 bits.
+func PairKey(a, b int) uint64 {
+	return uint64(uint32(min(a, b)))<<32 | uint64(uint32(max(a, b)))
+}
+
+// ContactTracker turns the overlapping pairs of each frame into begin, stay and end events.
+//
+// Add the pairs found by any overlap function or broadphase during a frame, then call Update().
+type ContactTracker struct {
+	Events []ContactEvent // Events of the last Update(), sorted by Key
+
+	sensors  map[int]bool
+	current  map[uint64]ContactEvent
+	previous map[uint64]ContactEvent
+}
+
+// NewContactTracker returns new ContactTracker
+func NewContactTracker() *ContactTracker {
+	return &ContactTracker{
+		sensors:  make(map[int]bool),
+		current:  make(map[uint64]ContactEvent),
+		previous: make(map[uint64]ContactEvent),
+	}
+}
+
+// SetSensor marks id as a sensor or a normal shape.
+func (t *ContactTracker) SetSensor(id int, sensor bool) {
+	if sensor {
+		t.sensors[id] = true
+	} else {
+		delete(t.sensors, id)
+	}
+}
+
+// IsSensor returns true if id is marked as a sensor.
+func (t *ContactTracker) IsSensor(id int) bool { return t.sensors[id] }
+
+// Add records that a and b touch in the current frame. h can be nil.
+// If the pair is added more than once in a frame, the last hit is kept.
+func (t *ContactTracker) Add(a, b int, h *Hit) {
+	e := ContactEvent{Key: PairKey(a, b), A: a, B: b, Sensor: t.sensors[a] || t.sensors[b]}
+	if h != nil {
+		e.Hit = *h
+	}
+	t.current[e.Key] = e
+}
+
+// AddPairs records all pairs without hit info, e.g. the pairs found by a broadphase.
+func (t *ContactTracker) AddPairs(pairs [][2]int) {
+	for _, p := range pairs {
+		t.Add(p[0], p[1], nil)
+	}
+}
+
+// Update compares the pairs added in this frame with the previous frame,
+// fills Events and starts a new frame.
+func (t *ContactTracker) Update() []ContactEvent {
+	t.Events = t.Events[:0]
+	for key, e := range t.current {
+		e.Type = ContactBegin
+		if _, ok := t.previous[key]; ok {
+			e.Type = ContactStay
+		}
+		t.Events = append(t.Events, e)
+	}
+	for key, e := range t.previous {
+		if _, ok := t.current[key]; !ok {
+			e.Type = ContactEnd
+			e.Sensor = t.sensors[e.A] || t.sensors[e.B]
+			t.Events = append(t.Events, e)
+		}
+	}
+	slices.SortFunc(t.Events, func(a, b ContactEvent) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	t.previous, t.current = t.current, t.previous
+	clear(t.current)
+	return t.Events
+}
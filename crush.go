@@ -0,0 +1,94 @@
+package coll
+
+import (
+	"image"
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// Crush describes a box squeezed between a moving pusher and a solid
+type Crush struct {
+	PusherNormal v.Vec       // Surface normal of the pusher for the box
+	SolidNormal  v.Vec       // Surface normal of the blocking solid for the box
+	Depth        float64     // Overlap of the pusher and the box along PusherNormal that could not be resolved
+	Solid        int         // Index of the blocking AABB solid, or -1 if the solid is a tile
+	TileCoords   image.Point // X,Y coordinates of the blocking tile if Solid is -1
+}
+
+// PushBox moves pusher by pusherDelta and pushes box b out of its way.
+// The box stops at solids and at the solid tiles of c (c can be nil).
+// pusher is moved, b is moved as far as possible.
+//
+// Returns the movement applied to b. If the pusher still overlaps the box at the end,
+// the box has no valid position left: crushed is true and cr is filled if not nil.
+func PushBox(pusher *AABB, pusherDelta v.Vec, b *AABB, solids []*AABB, c *TileCollider, cr *Crush) (delta v.Vec, crushed bool) {
+	var h Hit
+	hit := BoxBoxSweep2(b, pusher, v.Vec{}, pusherDelta, &h)
+	pusher.Pos = pusher.Pos.Add(pusherDelta)
+	if !hit {
+		return v.Vec{}, false
+	}
+	// h.Normal is for the pusher, the box is pushed to the other side
+	normal := h.Normal.Neg()
+	var blocked Crush
+	delta = moveBlocked(b, pushOut(pusher, b, normal), pusher, solids, c, &blocked)
+	return delta, checkCrush(pusher, b, normal, &blocked, cr)
+}
+
+// checkCrush reports a crush in cr if pusher overlaps b after b was moved
+func checkCrush(pusher, b *AABB, pusherNormal v.Vec, blocked, cr *Crush) bool {
+	var h Hit
+	if !BoxBoxOverlap(pusher, b, &h) || h.Data <= Epsilon {
+		return false
+	}
+	if cr != nil {
+		// distance b would have to move along the pusher normal to leave the pusher
+		hSum := pusher.Half.Add(b.Half)
+		r := hSum.X*math.Abs(pusherNormal.X) + hSum.Y*math.Abs(pusherNormal.Y)
+		*cr = *blocked
+		cr.PusherNormal = pusherNormal
+		cr.Depth = r - b.Pos.Sub(pusher.Pos).Dot(pusherNormal)
+	}
+	return true
+}
+
+// moveBlocked moves b by delta, X first and then Y, and stops each axis at the first of solids
+// or solid tiles of c that is hit. ignore is skipped in solids.
+// The last blocking solid is stored in blocked. Returns the applied movement.
+func moveBlocked(b *AABB, delta v.Vec, ignore *AABB, solids []*AABB, c *TileCollider, blocked *Crush) v.Vec {
+	var h Hit
+	applied := v.Vec{}
+	for _, d := range [2]v.Vec{{X: delta.X}, {Y: delta.Y}} {
+		if d.IsZero() {
+			continue
+		}
+		t := 1.0
+		for i, s := range solids {
+			if s == b || s == ignore || !BoxBoxSweep1(s, b, d, &h) || h.Normal.Dot(d) >= 0 || h.Data >= t {
+				continue
+			}
+			t = h.Data
+			*blocked = Crush{SolidNormal: h.Normal, Solid: i}
+		}
+		if c != nil {
+			n := len(c.Collisions)
+			var allowed float64
+			if d.X != 0 {
+				allowed = c.CollideX(b, d.X) / d.X
+			} else {
+				allowed = c.CollideY(b, d.Y) / d.Y
+			}
+			if len(c.Collisions) > n && allowed < t {
+				t = max(allowed, 0)
+				hit := c.Collisions[len(c.Collisions)-1]
+				*blocked = Crush{SolidNormal: hit.Normal, Solid: -1, TileCoords: hit.TileCoords}
+			}
+			c.Collisions = c.Collisions[:n]
+		}
+		d = d.Scale(t)
+		b.Pos = b.Pos.Add(d)
+		applied = applied.Add(d)
+	}
+	return applied
+}
@@ -0,0 +1,142 @@
+package coll
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// depenetrateIterations is the number of relaxation passes of Depenetrate
+const depenetrateIterations = 8
+
+// Depenetrate finds the position closest to box.Pos where box doesn't overlap any of solids.
+// Solids can be *AABB, *Circle, *Segment and *OBB shapes, other types are ignored.
+// Segments are two-sided. Touching is not overlapping.
+//
+// The overlaps are resolved together by moving the box out of each solid in turn,
+// starting from box.Pos and from positions next to the sides of the solids.
+// The result is exact for boxes and may be slightly farther than needed for curved
+// and rotated solids.
+//
+// Returns false if there is no free position within maxDistance of box.Pos.
+// box is not modified.
+func Depenetrate(box *AABB, maxDistance float64, solids ...any) (pos v.Vec, ok bool) {
+	b := *box
+	free := func(p v.Vec) bool {
+		b.Pos = p
+		for _, s := range solids {
+			if _, hit := boxPush(&b, s); hit {
+				return false
+			}
+		}
+		return true
+	}
+	// relax moves the box out of each overlapping solid in turn
+	relax := func(p v.Vec) v.Vec {
+		b.Pos = p
+		for range depenetrateIterations {
+			moved := false
+			for _, s := range solids {
+				if push, hit := boxPush(&b, s); hit {
+					b.Pos = b.Pos.Add(push)
+					moved = true
+				}
+			}
+			if !moved {
+				break
+			}
+		}
+		return b.Pos
+	}
+
+	if free(box.Pos) {
+		return box.Pos, true
+	}
+	relaxed := relax(box.Pos)
+
+	// candidate positions: the relaxed position, the single pushes out of each solid,
+	// the sides of their bounding boxes and all combinations of their coordinates
+	candidates := []v.Vec{relaxed}
+	xs := []float64{box.Pos.X, relaxed.X}
+	ys := []float64{box.Pos.Y, relaxed.Y}
+	b.Pos = box.Pos
+	for _, s := range solids {
+		switch s.(type) {
+		case *AABB, *Circle, *Segment, *OBB:
+		default:
+			continue
+		}
+		bounds := shapeBounds(s)
+		xs = append(xs, bounds.Left()-b.Half.X, bounds.Right()+b.Half.X)
+		ys = append(ys, bounds.Top()-b.Half.Y, bounds.Bottom()+b.Half.Y)
+		if _, isBox := s.(*AABB); isBox {
+			continue
+		}
+		if push, hit := boxPush(&b, s); hit {
+			p := b.Pos.Add(push)
+			candidates = append(candidates, p)
+			xs = append(xs, p.X)
+			ys = append(ys, p.Y)
+		}
+	}
+	for _, x := range xs {
+		for _, y := range ys {
+			candidates = append(candidates, v.Vec{X: x, Y: y})
+		}
+	}
+	slices.SortFunc(candidates, func(p, q v.Vec) int {
+		return cmp.Compare(p.DistSq(box.Pos), q.DistSq(box.Pos))
+	})
+
+	// the candidates are relaxed too, as the sides of the bounding boxes of curved
+	// and rotated solids are not the closest free positions
+	bestDistSq := maxDistance * maxDistance
+	for _, p := range candidates {
+		if p.DistSq(box.Pos) > bestDistSq {
+			break
+		}
+		q := relax(p)
+		if d := q.DistSq(box.Pos); d <= bestDistSq && free(q) {
+			pos, ok, bestDistSq = q, true, d
+		}
+	}
+	if !ok {
+		return box.Pos, false
+	}
+	return pos, true
+}
+
+// boxPush returns the minimum translation that moves box b out of the solid shape s.
+// Returns false if they don't overlap by more than Epsilon.
+func boxPush(b *AABB, s any) (push v.Vec, hit bool) {
+	var h Hit
+	switch s := s.(type) {
+	case *AABB:
+		if !BoxBoxOverlap(s, b, &h) {
+			return v.Vec{}, false
+		}
+	case *Circle:
+		if !BoxCircleOverlap(b, s, &h) {
+			return v.Vec{}, false
+		}
+		// the normal points from the box to the circle
+		h.Normal = h.Normal.Neg()
+	case *Segment:
+		if !BoxSegmentStaticOverlap(b, s, false, &h) {
+			return v.Vec{}, false
+		}
+	case *OBB:
+		var c Contact
+		if !orientedBoxContact(&OBB{Pos: b.Pos, Half: b.Half}, s, &c) {
+			return v.Vec{}, false
+		}
+		h = Hit{Normal: c.Normal.Neg(), Data: c.Depth}
+	default:
+		return v.Vec{}, false
+	}
+	if h.Data <= Epsilon {
+		return v.Vec{}, false
+	}
+	return h.Normal.Scale(h.Data), true
+}
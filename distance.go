@@ -0,0 +1,198 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// Separation is the result of the distance queries.
+type Separation struct {
+	Distance float64 // Distance between the shapes
+	PointA   v.Vec   // Point of the first shape closest to the second shape
+	PointB   v.Vec   // Point of the second shape closest to the first shape
+}
+
+// Distance returns true if the shapes a and b are separated and fills s if not nil.
+// The shapes can be *AABB, *Circle, *Segment, *OBB and convex *Polygon.
+//
+// Returns false if the shapes overlap or touch, or if a shape type is not supported.
+func Distance(a, b any, s *Separation) bool {
+	va, ra, okA := convexCore(a)
+	vb, rb, okB := convexCore(b)
+	if !okA || !okB {
+		return false
+	}
+	return convexDistance(va, ra, vb, rb, s)
+}
+
+// BoxBoxDistance returns true if boxes a and b are separated and fills s if not nil.
+// When the boxes face each other, the witness points are in the middle of the facing part.
+func BoxBoxDistance(a, b *AABB, s *Separation) bool {
+	d := b.Pos.Sub(a.Pos)
+	gap := d.Abs().Sub(a.Half.Add(b.Half))
+	if gap.X <= 0 && gap.Y <= 0 {
+		return false
+	}
+	if s == nil {
+		return true
+	}
+	// witness coordinate on each axis
+	axis := func(ca, ha, cb, hb, d float64) (pa, pb float64) {
+		lo, hi := max(ca-ha, cb-hb), min(ca+ha, cb+hb)
+		if lo <= hi {
+			return (lo + hi) / 2, (lo + hi) / 2
+		}
+		sign := math.Copysign(1, d)
+		return ca + sign*ha, cb - sign*hb
+	}
+	s.PointA.X, s.PointB.X = axis(a.Pos.X, a.Half.X, b.Pos.X, b.Half.X, d.X)
+	s.PointA.Y, s.PointB.Y = axis(a.Pos.Y, a.Half.Y, b.Pos.Y, b.Half.Y, d.Y)
+	s.Distance = s.PointA.Dist(s.PointB)
+	return true
+}
+
+// BoxCircleDistance returns true if box a and circle c are separated and fills s if not nil.
+func BoxCircleDistance(a *AABB, c *Circle, s *Separation) bool {
+	p := ClosestPointOnBox(a, c.Pos)
+	return pointCircleDistance(p, c, s)
+}
+
+// CircleCircleDistance returns true if circles c1 and c2 are separated and fills s if not nil.
+func CircleCircleDistance(c1, c2 *Circle, s *Separation) bool {
+	dist := c1.Pos.Dist(c2.Pos)
+	if dist <= c1.Radius+c2.Radius {
+		return false
+	}
+	if s != nil {
+		dir := c2.Pos.Sub(c1.Pos).DivS(dist)
+		*s = Separation{
+			Distance: dist - c1.Radius - c2.Radius,
+			PointA:   c1.Pos.Add(dir.Scale(c1.Radius)),
+			PointB:   c2.Pos.Sub(dir.Scale(c2.Radius)),
+		}
+	}
+	return true
+}
+
+// CircleSegmentDistance returns true if circle c and segment seg are separated and fills s if not nil.
+// PointA is on the circle, PointB is on the segment.
+func CircleSegmentDistance(c *Circle, seg *Segment, s *Separation) bool {
+	p := ClosestPointOnSegment(seg, c.Pos)
+	if !pointCircleDistance(p, c, s) {
+		return false
+	}
+	if s != nil {
+		s.PointA, s.PointB = s.PointB, s.PointA
+	}
+	return true
+}
+
+// BoxSegmentDistance returns true if box a and segment seg are separated and fills s if not nil.
+func BoxSegmentDistance(a *AABB, seg *Segment, s *Separation) bool {
+	va, _ := boxVertices(a.Pos, a.Half, 0)
+	return convexDistance(va[:], 0, []v.Vec{seg.A, seg.B}, 0, s)
+}
+
+// SegmentSegmentDistance returns true if segments a and b are separated and fills s if not nil.
+func SegmentSegmentDistance(a, b *Segment, s *Separation) bool {
+	return convexDistance([]v.Vec{a.A, a.B}, 0, []v.Vec{b.A, b.B}, 0, s)
+}
+
+// pointCircleDistance fills s with the separation of point p and circle c.
+// PointA is p, PointB is on the circle.
+func pointCircleDistance(p v.Vec, c *Circle, s *Separation) bool {
+	dist := p.Dist(c.Pos)
+	if dist <= c.Radius {
+		return false
+	}
+	if s != nil {
+		*s = Separation{
+			Distance: dist - c.Radius,
+			PointA:   p,
+			PointB:   c.Pos.Add(p.Sub(c.Pos).DivS(dist).Scale(c.Radius)),
+		}
+	}
+	return true
+}
+
+// convexCore returns a shape as the convex hull of points inflated by radius
+func convexCore(shape any) (points []v.Vec, radius float64, ok bool) {
+	switch s := shape.(type) {
+	case *AABB:
+		verts, _ := boxVertices(s.Pos, s.Half, 0)
+		return verts[:], 0, true
+	case *OBB:
+		verts, _ := boxVertices(s.Pos, s.Half, s.Angle)
+		return verts[:], 0, true
+	case *Polygon:
+		return s.Points, 0, len(s.Points) > 0
+	case *Segment:
+		return []v.Vec{s.A, s.B}, 0, true
+	case *Circle:
+		return []v.Vec{s.Pos}, s.Radius, true
+	}
+	return nil, 0, false
+}
+
+// convexDistance returns true if the convex hulls of va and vb, inflated by ra and rb, are separated.
+func convexDistance(va []v.Vec, ra float64, vb []v.Vec, rb float64, s *Separation) bool {
+	if convexCoresOverlap(va, vb) {
+		return false
+	}
+
+	// the closest points of separated convex shapes are a vertex and a point on an edge
+	pa, pb := va[0], vb[0]
+	bestDistSq := math.Inf(1)
+	for _, p := range vb {
+		if q := closestPointOnEdges(va, p); q.DistSq(p) < bestDistSq {
+			pa, pb, bestDistSq = q, p, q.DistSq(p)
+		}
+	}
+	for _, p := range va {
+		if q := closestPointOnEdges(vb, p); q.DistSq(p) < bestDistSq {
+			pa, pb, bestDistSq = p, q, q.DistSq(p)
+		}
+	}
+
+	dist := math.Sqrt(bestDistSq)
+	if dist <= ra+rb {
+		return false
+	}
+	if s != nil {
+		dir := pb.Sub(pa).DivS(dist)
+		*s = Separation{
+			Distance: dist - ra - rb,
+			PointA:   pa.Add(dir.Scale(ra)),
+			PointB:   pb.Sub(dir.Scale(rb)),
+		}
+	}
+	return true
+}
+
+// convexCoresOverlap returns true if the convex hulls of va and vb intersect
+func convexCoresOverlap(va, vb []v.Vec) bool {
+	if pointInPolygon(va, vb[0]) || pointInPolygon(vb, va[0]) {
+		return true
+	}
+	edge := func(points []v.Vec, i int) Segment {
+		return Segment{A: points[i], B: points[(i+1)%len(points)]}
+	}
+	for i := range edgeCount(va) {
+		ea := edge(va, i)
+		for j := range edgeCount(vb) {
+			if eb := edge(vb, j); SegmentSegmentOverlap(&ea, &eb, nil) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// edgeCount returns the number of edges of the polygon points, one for a single point or segment
+func edgeCount(points []v.Vec) int {
+	if len(points) <= 2 {
+		return 1
+	}
+	return len(points)
+}
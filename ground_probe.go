@@ -0,0 +1,89 @@
+package coll
+
+import (
+	"image"
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// GroundProbe finds the ground below a platformer character and keeps it attached to slopes.
+//
+// Call Probe() after moving the character, then Snap() to pull the box back onto the ground
+// when it would otherwise leave a slope for a frame.
+type GroundProbe struct {
+	Distance     float64 // How far below the box to search for ground
+	SnapDistance float64 // Ground closer than this is snapped to (should be <= Distance)
+	MaxSlope     float64 // Maximum walkable slope angle in radians
+}
+
+// GroundHit stores the ground found by a GroundProbe
+type GroundHit struct {
+	Normal     v.Vec       // Surface normal of the ground
+	Angle      float64     // Slope angle in radians (0.0 for flat ground)
+	Distance   float64     // Distance the box can move down until it rests on the ground
+	Walkable   bool        // Angle is not larger than MaxSlope
+	Segment    int         // Index of the ground segment, or -1 if the ground is a tile
+	TileCoords image.Point // X,Y coordinates of the ground tile if Segment is -1
+}
+
+// NewGroundProbe returns new GroundProbe
+func NewGroundProbe(distance, snapDistance, maxSlope float64) *GroundProbe {
+	return &GroundProbe{Distance: distance, SnapDistance: snapDistance, MaxSlope: maxSlope}
+}
+
+// Probe sweeps box a down by Distance against segs and the solid tiles of c and reports
+// the closest ground in g. segs and c can be nil. c.Collisions is left unchanged.
+//
+// Returns false if there is no ground within Distance.
+func (p *GroundProbe) Probe(a *AABB, segs []*Segment, c *TileCollider, g *GroundHit) bool {
+	found := false
+	var res GroundHit
+	down := v.Vec{Y: p.Distance}
+
+	var h Hit
+	if i := BoxSegmentsSweep1Indexed(segs, a, down, &h); i != -1 {
+		found = true
+		res = GroundHit{
+			Normal:   h.Normal,
+			Distance: max(h.Data*p.Distance-Padding, 0),
+			Segment:  i,
+		}
+	}
+
+	if c != nil {
+		n := len(c.Collisions)
+		box := *a
+		dist := c.CollideY(&box, p.Distance)
+		if len(c.Collisions) > n && (!found || dist < res.Distance) {
+			found = true
+			res = GroundHit{
+				Normal:     v.Up,
+				Distance:   max(dist, 0),
+				Segment:    -1,
+				TileCoords: c.Collisions[len(c.Collisions)-1].TileCoords,
+			}
+		}
+		c.Collisions = c.Collisions[:n]
+	}
+
+	if !found {
+		return false
+	}
+	res.Angle = math.Acos(max(-1, min(res.Normal.Dot(v.Up), 1)))
+	res.Walkable = ClassifySurface(res.Normal, p.MaxSlope) == SurfaceFloor
+	if g != nil {
+		*g = res
+	}
+	return true
+}
+
+// Snap moves box a down onto the ground of g if the ground is walkable
+// and within SnapDistance. Returns true if the box was snapped.
+func (p *GroundProbe) Snap(a *AABB, g *GroundHit) bool {
+	if !g.Walkable || g.Distance > p.SnapDistance {
+		return false
+	}
+	a.Pos.Y += g.Distance
+	return true
+}
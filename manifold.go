@@ -0,0 +1,210 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// ManifoldPoint is a contact point of a Manifold
+type ManifoldPoint struct {
+	Point v.Vec   // Contact point in world space, halfway between the two surfaces
+	Depth float64 // Penetration depth of this point along the normal
+	// Stable ID of the touching features. It stays the same while the same edges and vertices
+	// are in contact, so it can be used to match points between frames for warm starting.
+	ID uint32
+}
+
+// Manifold holds the contact points of two overlapping convex shapes
+type Manifold struct {
+	Normal v.Vec            // Collision normal pointing from a to b
+	Points [2]ManifoldPoint // Contact points, only the first Count are valid
+	Count  int              // Number of contact points (1 or 2)
+}
+
+// Feature types of a ManifoldPoint ID
+const (
+	manifoldVertex = 0
+	manifoldFace   = 1
+)
+
+// clipVertex is an end point of the incident edge during clipping
+type clipVertex struct {
+	v              v.Vec
+	indexA, indexB uint8
+	typeA, typeB   uint8
+}
+
+// id packs the features of cv into a ManifoldPoint ID
+func (cv *clipVertex) id() uint32 {
+	return uint32(cv.indexA) | uint32(cv.indexB)<<8 | uint32(cv.typeA)<<16 | uint32(cv.typeB)<<24
+}
+
+// BoxBoxManifold computes the contact manifold of two overlapping boxes.
+// Returns false if they don't overlap, touching is not overlapping.
+func BoxBoxManifold(a, b *AABB, m *Manifold) bool {
+	va, na := boxVertices(a.Pos, a.Half, 0)
+	vb, nb := boxVertices(b.Pos, b.Half, 0)
+	return polygonManifold(va[:], na[:], vb[:], nb[:], m)
+}
+
+// BoxOrientedBoxManifold computes the contact manifold of an overlapping AABB and OBB.
+// Returns false if they don't overlap, touching is not overlapping.
+func BoxOrientedBoxManifold(a *AABB, o *OBB, m *Manifold) bool {
+	va, na := boxVertices(a.Pos, a.Half, 0)
+	vb, nb := boxVertices(o.Pos, o.Half, o.Angle)
+	return polygonManifold(va[:], na[:], vb[:], nb[:], m)
+}
+
+// OrientedBoxOrientedBoxManifold computes the contact manifold of two overlapping OBBs.
+// Returns false if they don't overlap, touching is not overlapping.
+func OrientedBoxOrientedBoxManifold(a, b *OBB, m *Manifold) bool {
+	va, na := boxVertices(a.Pos, a.Half, a.Angle)
+	vb, nb := boxVertices(b.Pos, b.Half, b.Angle)
+	return polygonManifold(va[:], na[:], vb[:], nb[:], m)
+}
+
+// PolygonPolygonManifold computes the contact manifold of two overlapping convex polygons.
+// Returns false if they don't overlap, touching is not overlapping.
+func PolygonPolygonManifold(a, b *Polygon, m *Manifold) bool {
+	return polygonManifold(a.Points, polygonNormals(a.Points), b.Points, polygonNormals(b.Points), m)
+}
+
+// boxVertices returns the corners of a box (top-left first, positive area order) and its edge normals
+func boxVertices(pos, half v.Vec, angle float64) (verts, normals [4]v.Vec) {
+	x := v.FromAngle(angle)
+	y := v.Vec{X: -x.Y, Y: x.X}
+	ex, ey := x.Scale(half.X), y.Scale(half.Y)
+	verts = [4]v.Vec{
+		pos.Sub(ex).Sub(ey),
+		pos.Add(ex).Sub(ey),
+		pos.Add(ex).Add(ey),
+		pos.Sub(ex).Add(ey),
+	}
+	normals = [4]v.Vec{y.Neg(), x, y, x.Neg()}
+	return verts, normals
+}
+
+// polygonNormals returns the outward edge normals of a polygon
+func polygonNormals(points []v.Vec) []v.Vec {
+	normals := make([]v.Vec, len(points))
+	for i, p := range points {
+		normals[i] = SegmentNormal(p, points[(i+1)%len(points)])
+	}
+	return normals
+}
+
+// polygonManifold clips the incident edge against the reference face (Box2D b2CollidePolygons)
+func polygonManifold(va, na, vb, nb []v.Vec, m *Manifold) bool {
+	edgeA, sepA := maxSeparation(va, na, vb)
+	if sepA >= 0 {
+		return false
+	}
+	edgeB, sepB := maxSeparation(vb, nb, va)
+	if sepB >= 0 {
+		return false
+	}
+	if m == nil {
+		return true
+	}
+
+	// use the face of b as reference only if it is clearly better, to keep the features stable
+	ref, refNormals, inc, incNormals, edge, flip := va, na, vb, nb, edgeA, false
+	if sepB > 0.98*sepA+0.1*Padding {
+		ref, refNormals, inc, incNormals, edge, flip = vb, nb, va, na, edgeB, true
+	}
+	normal := refNormals[edge]
+
+	// incident edge is the edge of inc most anti-parallel to the reference normal
+	i1 := 0
+	minDot := math.Inf(1)
+	for i, n := range incNormals {
+		if d := n.Dot(normal); d < minDot {
+			minDot, i1 = d, i
+		}
+	}
+	i2 := (i1 + 1) % len(inc)
+	incident := [2]clipVertex{
+		{v: inc[i1], indexA: uint8(edge), indexB: uint8(i1), typeA: manifoldFace, typeB: manifoldVertex},
+		{v: inc[i2], indexA: uint8(edge), indexB: uint8(i2), typeA: manifoldFace, typeB: manifoldVertex},
+	}
+
+	// clip against the side planes of the reference face
+	edge2 := (edge + 1) % len(ref)
+	v11, v12 := ref[edge], ref[edge2]
+	tangent := v12.Sub(v11).Unit()
+	clip1, ok := clipSegmentToLine(incident, tangent.Neg(), -tangent.Dot(v11), edge)
+	if !ok {
+		return false
+	}
+	clip2, ok := clipSegmentToLine(clip1, tangent, tangent.Dot(v12), edge2)
+	if !ok {
+		return false
+	}
+
+	frontOffset := normal.Dot(v11)
+	m.Normal = normal
+	if flip {
+		m.Normal = normal.Neg()
+	}
+	m.Count = 0
+	for _, cv := range clip2 {
+		separation := normal.Dot(cv.v) - frontOffset
+		if separation >= 0 {
+			continue
+		}
+		if flip {
+			cv.indexA, cv.indexB = cv.indexB, cv.indexA
+			cv.typeA, cv.typeB = cv.typeB, cv.typeA
+		}
+		m.Points[m.Count] = ManifoldPoint{
+			Point: cv.v.Sub(normal.Scale(separation * 0.5)),
+			Depth: -separation,
+			ID:    cv.id(),
+		}
+		m.Count++
+	}
+	return m.Count > 0
+}
+
+// maxSeparation returns the edge of polygon 1 with the largest separation from polygon 2
+func maxSeparation(v1, n1, v2 []v.Vec) (edge int, separation float64) {
+	separation = math.Inf(-1)
+	for i, n := range n1 {
+		s := math.Inf(1)
+		for _, p := range v2 {
+			s = min(s, n.Dot(p.Sub(v1[i])))
+		}
+		if s > separation {
+			edge, separation = i, s
+		}
+	}
+	return edge, separation
+}
+
+// clipSegmentToLine keeps the part of the segment behind the line normal·p = offset (Sutherland-Hodgman).
+// Returns false if less than two points are left.
+func clipSegmentToLine(in [2]clipVertex, normal v.Vec, offset float64, vertexIndexA int) (out [2]clipVertex, ok bool) {
+	n := 0
+	d0 := normal.Dot(in[0].v) - offset
+	d1 := normal.Dot(in[1].v) - offset
+	if d0 <= 0 {
+		out[n] = in[0]
+		n++
+	}
+	if d1 <= 0 {
+		out[n] = in[1]
+		n++
+	}
+	if d0*d1 < 0 {
+		out[n] = clipVertex{
+			v:      in[0].v.Add(in[1].v.Sub(in[0].v).Scale(d0 / (d0 - d1))),
+			indexA: uint8(vertexIndexA),
+			indexB: in[0].indexB,
+			typeA:  manifoldVertex,
+			typeB:  manifoldFace,
+		}
+		n++
+	}
+	return out, n == 2
+}
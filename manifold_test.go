@@ -0,0 +1,53 @@
+package coll
+
+import (
+	"testing"
+
+	"github.com/setanarut/v"
+)
+
+func TestManifoldTouching(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      *AABB
+		want      bool
+		wantCount int
+		wantDepth float64
+	}{
+		{"separated", NewAABB(0, 0, 1, 1), NewAABB(3, 0, 1, 1), false, 0, 0},
+		{"touching side", NewAABB(0, 0, 1, 1), NewAABB(2, 0, 1, 1), false, 0, 0},
+		{"touching corner", NewAABB(0, 0, 1, 1), NewAABB(2, 2, 1, 1), false, 0, 0},
+		{"overlapping", NewAABB(0, 0, 1, 1), NewAABB(1.5, 0, 1, 1), true, 2, 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Manifold
+			if got := BoxBoxManifold(tt.a, tt.b, &m); got != tt.want {
+				t.Fatalf("BoxBoxManifold = %v, want %v", got, tt.want)
+			}
+			if got := BoxBoxManifold(tt.a, tt.b, nil); got != tt.want {
+				t.Errorf("BoxBoxManifold without manifold = %v, want %v", got, tt.want)
+			}
+			oa := &OBB{Pos: tt.a.Pos, Half: tt.a.Half}
+			ob := &OBB{Pos: tt.b.Pos, Half: tt.b.Half}
+			if got := OrientedBoxOrientedBoxManifold(oa, ob, nil); got != tt.want {
+				t.Errorf("OrientedBoxOrientedBoxManifold = %v, want %v", got, tt.want)
+			}
+			if !tt.want {
+				return
+			}
+			if m.Count != tt.wantCount {
+				t.Fatalf("Count = %d, want %d", m.Count, tt.wantCount)
+			}
+			if m.Normal != (v.Vec{X: 1}) {
+				t.Errorf("Normal = %v, want (1, 0)", m.Normal)
+			}
+			for _, p := range m.Points[:m.Count] {
+				if !almostEqual(p.Depth, tt.wantDepth) {
+					t.Errorf("Depth = %v, want %v", p.Depth, tt.wantDepth)
+				}
+			}
+		})
+	}
+}
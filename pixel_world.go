@@ -0,0 +1,226 @@
+package coll
+
+import (
+	"image"
+	"math"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// PixelCollisionCallback is called when an Actor is blocked. normal is the surface normal
+// of the blocking solid for the actor, s is nil if the actor was blocked by a tile.
+type PixelCollisionCallback func(normal image.Point, s *Solid)
+
+// PixelWorld is an integer movement system for pixel-art games.
+//
+// Actors and solids have integer rectangles. Fractional movement is accumulated in
+// a remainder and applied one pixel at a time, so actors never overlap solids.
+// Solids are not blocked by anything, they push and carry actors.
+// This follows the Celeste and TowerFall physics approach.
+type PixelWorld struct {
+	Actors []*Actor
+	Solids []*Solid
+	Tiles  *TileCollider // Solid tiles block actors (optional)
+}
+
+// Actor is a moving rectangle that is blocked by solids and tiles
+type Actor struct {
+	Rect      image.Rectangle
+	Remainder v.Vec // Movement not applied yet (-0.5 to 0.5)
+	// Called when a solid pushes the actor into another solid or tile.
+	// The actor should usually be killed or removed.
+	OnSquish PixelCollisionCallback
+	world    *PixelWorld
+}
+
+// Solid is a moving rectangle that blocks actors
+type Solid struct {
+	Rect       image.Rectangle
+	Remainder  v.Vec // Movement not applied yet (-0.5 to 0.5)
+	Collidable bool  // If false, actors move through the solid
+	world      *PixelWorld
+}
+
+// NewPixelWorld returns new PixelWorld. tiles can be nil.
+func NewPixelWorld(tiles *TileCollider) *PixelWorld {
+	return &PixelWorld{Tiles: tiles}
+}
+
+// AddActor adds a new actor with rect to the world and returns it.
+func (w *PixelWorld) AddActor(rect image.Rectangle) *Actor {
+	a := &Actor{Rect: rect, world: w}
+	w.Actors = append(w.Actors, a)
+	return a
+}
+
+// AddSolid adds a new collidable solid with rect to the world and returns it.
+func (w *PixelWorld) AddSolid(rect image.Rectangle) *Solid {
+	s := &Solid{Rect: rect, Collidable: true, world: w}
+	w.Solids = append(w.Solids, s)
+	return s
+}
+
+// RemoveActor removes a from the world.
+func (w *PixelWorld) RemoveActor(a *Actor) {
+	w.Actors = slices.DeleteFunc(w.Actors, func(x *Actor) bool { return x == a })
+}
+
+// RemoveSolid removes s from the world.
+func (w *PixelWorld) RemoveSolid(s *Solid) {
+	w.Solids = slices.DeleteFunc(w.Solids, func(x *Solid) bool { return x == s })
+}
+
+// CollideAt returns true if rect overlaps a collidable solid or a solid tile.
+// The solid is returned, or nil for tiles.
+func (w *PixelWorld) CollideAt(rect image.Rectangle) (bool, *Solid) {
+	for _, s := range w.Solids {
+		if s.Collidable && s.Rect.Overlaps(rect) {
+			return true, s
+		}
+	}
+	return w.collideTiles(rect), nil
+}
+
+// collideTiles returns true if rect overlaps a solid tile
+func (w *PixelWorld) collideTiles(rect image.Rectangle) bool {
+	c := w.Tiles
+	if c == nil || rect.Empty() || len(c.TileMap) == 0 {
+		return false
+	}
+	x0 := max(floorDiv(rect.Min.X, c.CellSize.X), 0)
+	y0 := max(floorDiv(rect.Min.Y, c.CellSize.Y), 0)
+	x1 := min(floorDiv(rect.Max.X-1, c.CellSize.X), len(c.TileMap[0])-1)
+	y1 := min(floorDiv(rect.Max.Y-1, c.CellSize.Y), len(c.TileMap)-1)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if t := c.TileMap[y][x]; t != nil && t.IsSolid() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MoveX moves the actor by amount pixels along the X axis, one pixel at a time.
+// Stops in front of the first solid and calls onCollide (can be nil).
+// Returns true if the actor was blocked.
+func (a *Actor) MoveX(amount float64, onCollide PixelCollisionCallback) bool {
+	a.Remainder.X += amount
+	move := int(math.Round(a.Remainder.X))
+	if move == 0 {
+		return false
+	}
+	a.Remainder.X -= float64(move)
+	return a.move(image.Point{X: sign(move)}, move, onCollide)
+}
+
+// MoveY moves the actor by amount pixels along the Y axis, one pixel at a time.
+// Stops in front of the first solid and calls onCollide (can be nil).
+// Returns true if the actor was blocked.
+func (a *Actor) MoveY(amount float64, onCollide PixelCollisionCallback) bool {
+	a.Remainder.Y += amount
+	move := int(math.Round(a.Remainder.Y))
+	if move == 0 {
+		return false
+	}
+	a.Remainder.Y -= float64(move)
+	return a.move(image.Point{Y: sign(move)}, move, onCollide)
+}
+
+// move moves the actor |move| pixels in the direction of step
+func (a *Actor) move(step image.Point, move int, onCollide PixelCollisionCallback) bool {
+	for range max(move, -move) {
+		if hit, s := a.world.CollideAt(a.Rect.Add(step)); hit {
+			if onCollide != nil {
+				onCollide(step.Mul(-1), s)
+			}
+			return true
+		}
+		a.Rect = a.Rect.Add(step)
+	}
+	return false
+}
+
+// IsRiding returns true if the actor stands on top of s.
+func (a *Actor) IsRiding(s *Solid) bool {
+	return a.Rect.Max.Y == s.Rect.Min.Y && a.Rect.Min.X < s.Rect.Max.X && a.Rect.Max.X > s.Rect.Min.X
+}
+
+// Move moves the solid by x, y pixels. Actors riding the solid are carried, actors in the way
+// are pushed. Pushed actors that are blocked by another solid are squished (see Actor.OnSquish).
+func (s *Solid) Move(x, y float64) {
+	s.Remainder = s.Remainder.Add(v.Vec{X: x, Y: y})
+	moveX := int(math.Round(s.Remainder.X))
+	moveY := int(math.Round(s.Remainder.Y))
+	if moveX == 0 && moveY == 0 {
+		return
+	}
+
+	var riding []*Actor
+	for _, a := range s.world.Actors {
+		if a.IsRiding(s) {
+			riding = append(riding, a)
+		}
+	}
+
+	// actors must not collide with the solid while it pushes them
+	collidable := s.Collidable
+	s.Collidable = false
+
+	if moveX != 0 {
+		s.Remainder.X -= float64(moveX)
+		s.Rect = s.Rect.Add(image.Point{X: moveX})
+		for _, a := range s.world.Actors {
+			switch {
+			case collidable && a.Rect.Overlaps(s.Rect):
+				if moveX > 0 {
+					a.MoveX(float64(s.Rect.Max.X-a.Rect.Min.X), a.OnSquish)
+				} else {
+					a.MoveX(float64(s.Rect.Min.X-a.Rect.Max.X), a.OnSquish)
+				}
+			case slices.Contains(riding, a):
+				a.MoveX(float64(moveX), nil)
+			}
+		}
+	}
+
+	if moveY != 0 {
+		s.Remainder.Y -= float64(moveY)
+		s.Rect = s.Rect.Add(image.Point{Y: moveY})
+		for _, a := range s.world.Actors {
+			switch {
+			case collidable && a.Rect.Overlaps(s.Rect):
+				if moveY > 0 {
+					a.MoveY(float64(s.Rect.Max.Y-a.Rect.Min.Y), a.OnSquish)
+				} else {
+					a.MoveY(float64(s.Rect.Min.Y-a.Rect.Max.Y), a.OnSquish)
+				}
+			case slices.Contains(riding, a):
+				a.MoveY(float64(moveY), nil)
+			}
+		}
+	}
+
+	s.Collidable = collidable
+}
+
+// sign returns -1, 0 or 1
+func sign(x int) int {
+	switch {
+	case x < 0:
+		return -1
+	case x > 0:
+		return 1
+	}
+	return 0
+}
+
+// floorDiv returns a/b rounded toward negative infinity
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
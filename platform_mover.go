@@ -0,0 +1,102 @@
+package coll
+
+import (
+	"github.com/setanarut/v"
+)
+
+// PlatformContact describes a box moved by PlatformMover.Move()
+type PlatformContact struct {
+	Index   int   // Index of the box in the slice passed to Move()
+	Riding  bool  // The box stands on the platform and was carried, otherwise it was pushed
+	Normal  v.Vec // Surface normal of the platform for the box
+	Delta   v.Vec // Movement applied to the box
+	Crushed bool  // The box is squeezed between the platform and a solid, see Crush
+	Crush   Crush // Crush info, only valid if Crushed is true
+}
+
+// PlatformMover moves solid platforms and the boxes riding on or pushed by them.
+//
+// Move the platforms before the other boxes in each frame,
+// so the boxes add their own movement on top of the platform movement.
+type PlatformMover struct {
+	// Maximum gap between the bottom of a box and the top of the platform
+	// for the box to count as standing on the platform
+	RideTolerance float64
+	Contacts      []PlatformContact // Boxes moved by the last Move(), in the order of boxes
+	Solids        []*AABB           // Static boxes that block the moved boxes (optional)
+	Tiles         *TileCollider     // Solid tiles that block the moved boxes (optional)
+}
+
+// NewPlatformMover returns new PlatformMover
+func NewPlatformMover(rideTolerance float64) *PlatformMover {
+	return &PlatformMover{RideTolerance: rideTolerance}
+}
+
+// IsRiding returns true if box b stands on platform within RideTolerance.
+func (m *PlatformMover) IsRiding(platform, b *AABB) bool {
+	if b.Right() <= platform.Left() || b.Left() >= platform.Right() {
+		return false
+	}
+	if b.Bottom() > platform.Top()+m.RideTolerance {
+		return false
+	}
+	var h Hit
+	return BoxBoxSweep2(platform, b, v.Vec{}, v.Vec{Y: m.RideTolerance}, &h) && h.Normal == v.Up
+}
+
+// Move moves platform by delta and moves the affected boxes, then returns Contacts.
+//
+// Boxes standing on the platform are carried by delta. Other boxes that the platform
+// moves into are pushed out of its way along the hit normal. Moved boxes stop at Solids and Tiles;
+// a box that still overlaps the platform after that is reported as crushed (see PushBox).
+func (m *PlatformMover) Move(platform *AABB, delta v.Vec, boxes []*AABB) []PlatformContact {
+	m.Contacts = m.Contacts[:0]
+	if delta.IsZero() {
+		return m.Contacts
+	}
+
+	start := *platform
+	moved := AABB{Pos: platform.Pos.Add(delta), Half: platform.Half}
+	for i, b := range boxes {
+		if b == platform {
+			continue
+		}
+		contact := PlatformContact{Index: i}
+		if m.IsRiding(&start, b) {
+			var blocked Crush
+			contact.Riding = true
+			contact.Normal = v.Up
+			contact.Delta = moveBlocked(b, delta, platform, m.Solids, m.Tiles, &blocked)
+			contact.Crushed = checkCrush(&moved, b, v.Up, &blocked, &contact.Crush)
+		} else {
+			contact.Delta, contact.Crushed = PushBox(platform, delta, b, m.Solids, m.Tiles, &contact.Crush)
+			platform.Pos = start.Pos
+			if contact.Delta.IsZero() && !contact.Crushed {
+				continue
+			}
+			contact.Normal = contact.Delta.Unit()
+			if contact.Crushed {
+				contact.Normal = contact.Crush.PusherNormal
+			}
+		}
+		m.Contacts = append(m.Contacts, contact)
+	}
+	platform.Pos = moved.Pos
+	return m.Contacts
+}
+
+// pushOut returns the movement of box b along the axis normal that moves it
+// to the side of a the normal points to. Returns zero if b is already there.
+func pushOut(a, b *AABB, normal v.Vec) v.Vec {
+	switch {
+	case normal.X > 0:
+		return v.Vec{X: max(a.Right()-b.Left(), 0)}
+	case normal.X < 0:
+		return v.Vec{X: min(a.Left()-b.Right(), 0)}
+	case normal.Y > 0:
+		return v.Vec{Y: max(a.Bottom()-b.Top(), 0)}
+	case normal.Y < 0:
+		return v.Vec{Y: min(a.Top()-b.Bottom(), 0)}
+	}
+	return v.Vec{}
+}
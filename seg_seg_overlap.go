@@ -0,0 +1,173 @@
+package coll
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/setanarut/v"
+)
+
+// orient2dErrBound is the relative error bound of the floating point orientation determinant
+// (Shewchuk's ccwerrboundA).
+const orient2dErrBound = (3 + 16*0x1p-53) * 0x1p-53
+
+// SegmentIntersection is the result of SegmentSegmentIntersection().
+type SegmentIntersection struct {
+	Point     v.Vec   // Intersection point, the start of the overlap closest to a.A if Collinear
+	T         float64 // Parameter of Point along a (0.0 at a.A, 1.0 at a.B)
+	U         float64 // Parameter of Point along b (0.0 at b.A, 1.0 at b.B)
+	Collinear bool    // The segments lie on the same line and overlap
+	Overlap   Segment // Common part of the segments from a.A toward a.B, A == B == Point unless Collinear
+}
+
+// SegmentSegmentOverlap returns true if segments a and b intersect. Touching end points intersect.
+//
+// If h is not nil and the segments intersect, it will be populated with:
+//   - Normal: Collision surface normal of b facing a.A (opposite to a if they are collinear)
+//   - Data: Parameter of the first intersection point along a (0.0 at a.A, 1.0 at a.B)
+func SegmentSegmentOverlap(a, b *Segment, h *Hit) bool {
+	var si SegmentIntersection
+	if !SegmentSegmentIntersection(a, b, &si) {
+		return false
+	}
+	if h != nil {
+		h.Data = si.T
+		h.Normal = SegmentNormal(b.A, b.B)
+		if si.Collinear || h.Normal.IsZero() {
+			h.Normal = a.B.Sub(a.A).Unit().Neg()
+		} else if a.A.Sub(b.A).Dot(h.Normal) < 0 {
+			h.Normal = h.Normal.Neg()
+		}
+	}
+	return true
+}
+
+// SegmentSegmentIntersection returns true if segments a and b intersect and fills si if not nil.
+//
+// The side tests use exact orientation predicates, so nearly parallel segments and end points
+// lying on the other segment are classified correctly. Intersections at end points are reported
+// at exactly the end point.
+func SegmentSegmentIntersection(a, b *Segment, si *SegmentIntersection) bool {
+	o1 := orient2d(a.A, a.B, b.A)
+	o2 := orient2d(a.A, a.B, b.B)
+	o3 := orient2d(b.A, b.B, a.A)
+	o4 := orient2d(b.A, b.B, a.B)
+
+	if o1 == 0 && o2 == 0 && o3 == 0 && o4 == 0 {
+		return collinearSegmentIntersection(a, b, si)
+	}
+	if (o1 > 0 && o2 > 0) || (o1 < 0 && o2 < 0) || (o3 > 0 && o4 > 0) || (o3 < 0 && o4 < 0) {
+		return false
+	}
+	if si == nil {
+		return true
+	}
+
+	// the parameters are the ratios of the distances of the end points to the other line
+	t := o3 / (o3 - o4)
+	u := o1 / (o1 - o2)
+	*si = SegmentIntersection{T: t, U: u}
+	switch {
+	case t == 0:
+		si.Point = a.A
+	case t == 1:
+		si.Point = a.B
+	case u == 0:
+		si.Point = b.A
+	case u == 1:
+		si.Point = b.B
+	default:
+		si.Point = a.A.Add(a.B.Sub(a.A).Scale(t))
+	}
+	si.Overlap = Segment{A: si.Point, B: si.Point}
+	return true
+}
+
+// collinearSegmentIntersection intersects segments a and b lying on the same line.
+// Either segment can be a single point.
+func collinearSegmentIntersection(a, b *Segment, si *SegmentIntersection) bool {
+	d, e := a.B.Sub(a.A), b.B.Sub(b.A)
+	var res SegmentIntersection
+	switch {
+	case d.IsZero() && e.IsZero():
+		if a.A != b.A {
+			return false
+		}
+		res.Point = a.A
+	case d.IsZero():
+		u := a.A.Sub(b.A).Dot(e) / e.MagSq()
+		if u < 0 || u > 1 {
+			return false
+		}
+		res = SegmentIntersection{Point: a.A, U: u}
+	case e.IsZero():
+		t := b.A.Sub(a.A).Dot(d) / d.MagSq()
+		if t < 0 || t > 1 {
+			return false
+		}
+		res = SegmentIntersection{Point: b.A, T: t}
+	default:
+		t0 := b.A.Sub(a.A).Dot(d) / d.MagSq()
+		t1 := b.B.Sub(a.A).Dot(d) / d.MagSq()
+		lo, hi := max(min(t0, t1), 0), min(max(t0, t1), 1)
+		if lo > hi {
+			return false
+		}
+		res = SegmentIntersection{T: lo, Collinear: true}
+		res.Point = a.A.Add(d.Scale(lo))
+		res.U = max(0, min(res.Point.Sub(b.A).Dot(e)/e.MagSq(), 1))
+		res.Overlap = Segment{A: res.Point, B: a.A.Add(d.Scale(hi))}
+	}
+	if si != nil {
+		if !res.Collinear {
+			res.Overlap = Segment{A: res.Point, B: res.Point}
+		}
+		*si = res
+	}
+	return true
+}
+
+// orient2d returns twice the signed area of the triangle abc. It is positive if c is on the side of
+// SegmentNormal(a, b), negative on the other side and zero if the points are collinear.
+// The sign is exact, the result is computed with rational arithmetic if floating point is not
+// accurate enough.
+func orient2d(a, b, c v.Vec) float64 {
+	abx, aby := b.X-a.X, b.Y-a.Y
+	cax, cay := a.X-c.X, a.Y-c.Y
+	left, right := abx*cay, aby*cax
+	det := left - right
+	// no cancellation if the products have different signs
+	if left == 0 || right == 0 || (left > 0) != (right > 0) {
+		return det
+	}
+	if math.Abs(det) >= orient2dErrBound*(math.Abs(left)+math.Abs(right)) {
+		return det
+	}
+	// nothing was rounded, like with integer coordinates
+	if diffTail(b.X, a.X, abx) == 0 && diffTail(b.Y, a.Y, aby) == 0 &&
+		diffTail(a.X, c.X, cax) == 0 && diffTail(a.Y, c.Y, cay) == 0 &&
+		math.FMA(abx, cay, -left) == 0 && math.FMA(aby, cax, -right) == 0 {
+		return det
+	}
+
+	r := func(f float64) *big.Rat { return new(big.Rat).SetFloat64(f) }
+	sub := func(x, y float64) *big.Rat { return new(big.Rat).Sub(r(x), r(y)) }
+	exact := new(big.Rat).Mul(sub(b.X, a.X), sub(a.Y, c.Y))
+	exact.Sub(exact, new(big.Rat).Mul(sub(b.Y, a.Y), sub(a.X, c.X)))
+	sign := exact.Sign()
+	if sign == 0 {
+		return 0
+	}
+	det, _ = exact.Float64()
+	if det == 0 {
+		det = math.SmallestNonzeroFloat64
+	}
+	return math.Copysign(det, float64(sign))
+}
+
+// diffTail returns the rounding error of the floating point difference x = a - b.
+func diffTail(a, b, x float64) float64 {
+	bv := a - x
+	av := x + bv
+	return (a - av) + (bv - b)
+}
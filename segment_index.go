@@ -0,0 +1,243 @@
+package coll
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// segmentLeafSize is the maximum number of segments in a leaf of SegmentIndex
+const segmentLeafSize = 4
+
+// SegmentIndex is an immutable bounding volume hierarchy of segments.
+//
+// It speeds up sweeps and ray casts against large static levels. The segments
+// are not copied, so they must not be changed after the index is built.
+type SegmentIndex struct {
+	segs  []*Segment
+	order []int // Segment indices sorted by leaf
+	nodes []segmentNode
+}
+
+type segmentNode struct {
+	box         AABB
+	left, right int // Child nodes, -1 for leaves
+	start, end  int // Range of order covered by a leaf
+}
+
+// NewSegmentIndex builds a new SegmentIndex from segs.
+func NewSegmentIndex(segs []*Segment) *SegmentIndex {
+	ix := &SegmentIndex{segs: segs, order: make([]int, len(segs))}
+	for i := range ix.order {
+		ix.order[i] = i
+	}
+	if len(segs) > 0 {
+		ix.build(0, len(segs))
+	}
+	return ix
+}
+
+// Len returns the number of segments in the index.
+func (ix *SegmentIndex) Len() int { return len(ix.segs) }
+
+// Segments returns the indexed segments.
+func (ix *SegmentIndex) Segments() []*Segment { return ix.segs }
+
+// build creates the node covering order[start:end] and returns its index
+func (ix *SegmentIndex) build(start, end int) int {
+	index := len(ix.nodes)
+	ix.nodes = append(ix.nodes, segmentNode{left: -1, right: -1, start: start, end: end})
+
+	box := segmentBox(ix.segs[ix.order[start]])
+	for _, i := range ix.order[start+1 : end] {
+		b := segmentBox(ix.segs[i])
+		box = unionBox(&box, &b)
+	}
+	ix.nodes[index].box = box
+	if end-start <= segmentLeafSize {
+		return index
+	}
+
+	// split at the median along the longest axis
+	center := func(i int) float64 {
+		if box.Half.X >= box.Half.Y {
+			return ix.segs[i].A.X + ix.segs[i].B.X
+		}
+		return ix.segs[i].A.Y + ix.segs[i].B.Y
+	}
+	slices.SortFunc(ix.order[start:end], func(a, b int) int {
+		return cmp.Compare(center(a), center(b))
+	})
+	mid := (start + end) / 2
+	left := ix.build(start, mid)
+	right := ix.build(mid, end)
+	ix.nodes[index].left = left
+	ix.nodes[index].right = right
+	return index
+}
+
+// BoxSegmentIndexSweep1 returns the index of the colliding segment in the slice used to build ix,
+// or -1 if no collision was detected.
+//
+// It returns the same results as BoxSegmentsSweep1Indexed(), but only tests the segments near
+// the path of the box.
+//
+// If h is not nil and a collision is detected, it will be populated with:
+//   - Normal: Collision surface normal for the box
+//   - Data: Normalized time of impact (0.0 to 1.0) along the movement path
+func BoxSegmentIndexSweep1(ix *SegmentIndex, a *AABB, deltaA v.Vec, h *Hit) (index int) {
+	colliderIndex := -1
+	var resHitTime float64
+	var tmpHitInfo Hit
+
+	if len(ix.nodes) == 0 {
+		return colliderIndex
+	}
+
+	var buf [64]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := &ix.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		enter, ok := boxBoundsEnterTime(&node.box, a, deltaA)
+		if !ok || (colliderIndex != -1 && enter > resHitTime) {
+			continue
+		}
+		if node.left != -1 {
+			stack = append(stack, node.right, node.left)
+			continue
+		}
+		for _, i := range ix.order[node.start:node.end] {
+			if !BoxSegmentSweep1(ix.segs[i], a, deltaA, &tmpHitInfo) {
+				continue
+			}
+			if colliderIndex == -1 || tmpHitInfo.Data < resHitTime || (tmpHitInfo.Data == resHitTime && i < colliderIndex) {
+				colliderIndex = i
+				resHitTime = tmpHitInfo.Data
+				if h != nil {
+					*h = tmpHitInfo
+				}
+			}
+		}
+	}
+	return colliderIndex
+}
+
+// BoxSegmentIndexSweep1Contact is like BoxSegmentIndexSweep1(), but fills a Contact for the box.
+// See BoxSegmentSweep1Contact().
+func BoxSegmentIndexSweep1Contact(ix *SegmentIndex, a *AABB, deltaA v.Vec, c *Contact) (index int) {
+	var h Hit
+	index = BoxSegmentIndexSweep1(ix, a, deltaA, &h)
+	if index != -1 && c != nil {
+		boxSegmentContact(ix.segs[index], a, deltaA, &h, c)
+	}
+	return index
+}
+
+// RaySegmentIndex casts the ray from start to start+delta against the segments of ix.
+// Returns the index of the closest segment hit, or -1 if nothing was hit.
+// Segments are two-sided for rays.
+//
+// If h is not nil and a collision is detected, it will be populated with:
+//   - Normal: Surface normal of the segment, facing the ray origin
+//   - Data: Normalized time of impact (0.0 to 1.0) along delta
+func RaySegmentIndex(ix *SegmentIndex, start, delta v.Vec, h *Hit) (index int) {
+	colliderIndex := -1
+	var resHitTime float64
+	var tmpHitInfo Hit
+
+	if len(ix.nodes) == 0 {
+		return colliderIndex
+	}
+
+	ray := AABB{Pos: start}
+	var buf [64]int
+	stack := append(buf[:0], 0)
+	for len(stack) > 0 {
+		node := &ix.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+
+		enter, ok := boxBoundsEnterTime(&node.box, &ray, delta)
+		if !ok || (colliderIndex != -1 && enter > resHitTime) {
+			continue
+		}
+		if node.left != -1 {
+			stack = append(stack, node.right, node.left)
+			continue
+		}
+		for _, i := range ix.order[node.start:node.end] {
+			if !raySegment(start, delta, ix.segs[i], &tmpHitInfo) {
+				continue
+			}
+			if colliderIndex == -1 || tmpHitInfo.Data < resHitTime || (tmpHitInfo.Data == resHitTime && i < colliderIndex) {
+				colliderIndex = i
+				resHitTime = tmpHitInfo.Data
+				if h != nil {
+					*h = tmpHitInfo
+				}
+			}
+		}
+	}
+	return colliderIndex
+}
+
+// raySegment intersects the ray from start to start+delta with segment s (two-sided).
+func raySegment(start, delta v.Vec, s *Segment, h *Hit) bool {
+	e := s.B.Sub(s.A)
+	denom := delta.Cross(e)
+	if math.Abs(denom) < Epsilon {
+		return false
+	}
+	d := s.A.Sub(start)
+	t := d.Cross(e) / denom
+	u := d.Cross(delta) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return false
+	}
+	if h != nil {
+		h.Normal = SegmentNormal(s.A, s.B)
+		if h.Normal.Dot(delta) > 0 {
+			h.Normal = h.Normal.Neg()
+		}
+		h.Data = t
+	}
+	return true
+}
+
+// boxBoundsEnterTime returns the time at which box a, moving by delta, first touches the static box b.
+// Returns false if they don't touch during the movement.
+func boxBoundsEnterTime(b, a *AABB, delta v.Vec) (float64, bool) {
+	enter, exit := 0.0, 1.0
+	d := a.Pos.Sub(b.Pos)
+	// small margin to stay conservative with rounding errors
+	hSum := a.Half.Add(b.Half).Add(v.Vec{X: Padding, Y: Padding})
+
+	for _, axis := range [2][3]float64{{d.X, delta.X, hSum.X}, {d.Y, delta.Y, hSum.Y}} {
+		pos, vel, ext := axis[0], axis[1], axis[2]
+		if vel == 0 {
+			if math.Abs(pos) > ext {
+				return 0, false
+			}
+			continue
+		}
+		t1 := (-ext - pos) / vel
+		t2 := (ext - pos) / vel
+		enter = max(enter, min(t1, t2))
+		exit = min(exit, max(t1, t2))
+		if enter > exit {
+			return 0, false
+		}
+	}
+	return enter, true
+}
+
+// segmentBox returns the bounding box of s
+func segmentBox(s *Segment) AABB {
+	return boundsBox(
+		v.Vec{X: min(s.A.X, s.B.X), Y: min(s.A.Y, s.B.Y)},
+		v.Vec{X: max(s.A.X, s.B.X), Y: max(s.A.Y, s.B.Y)},
+	)
+}
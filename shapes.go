@@ -64,6 +64,37 @@ type Segment struct {
 	A, B v.Vec
 }
 
+// SegmentChain is a polyline of connected segments from Points[i] to Points[i+1].
+// If Loop is true, the last point is also connected to the first point.
+type SegmentChain struct {
+	Points []v.Vec
+	Loop   bool
+}
+
+// Len returns the number of segments in the chain.
+func (c *SegmentChain) Len() int {
+	if len(c.Points) < 2 {
+		return 0
+	}
+	if c.Loop {
+		return len(c.Points)
+	}
+	return len(c.Points) - 1
+}
+
+// Segment returns the i-th segment of the chain.
+func (c *SegmentChain) Segment(i int) Segment {
+	return Segment{A: c.Points[i], B: c.Points[(i+1)%len(c.Points)]}
+}
+
+// Polygon is a convex polygon.
+//
+// The points must be ordered so that the polygon has a positive area
+// (clockwise on screen, with Y pointing down). Then SegmentNormal() of each edge points outward.
+type Polygon struct {
+	Points []v.Vec
+}
+
 // NewAABB returns new AABB
 func NewAABB(centerX, centerY, halfWidth, halfHeight float64) *AABB {
 	return &AABB{Pos: v.Vec{centerX, centerY}, Half: v.Vec{halfWidth, halfHeight}}
@@ -78,3 +109,13 @@ func NewCircle(x, y, radius float64) *Circle {
 func NewSegment(ax, ay, bx, by float64) *Segment {
 	return &Segment{A: v.Vec{ax, ay}, B: v.Vec{bx, by}}
 }
+
+// NewSegmentChain returns new SegmentChain
+func NewSegmentChain(loop bool, points ...v.Vec) *SegmentChain {
+	return &SegmentChain{Points: points, Loop: loop}
+}
+
+// NewPolygon returns new Polygon
+func NewPolygon(points ...v.Vec) *Polygon {
+	return &Polygon{Points: points}
+}
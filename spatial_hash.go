@@ -0,0 +1,202 @@
+package coll
+
+import (
+	"image"
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// SpatialHash is a uniform grid broadphase that maps boxes to the cells they overlap.
+//
+// Objects are identified by user IDs. Moving, removing and querying do not allocate
+// once the cells and the result slices have grown to their working size.
+//
+// CellSize should be about the size of a typical object. Objects much larger than a cell
+// are stored in many cells and slow down updates.
+type SpatialHash struct {
+	CellSize float64
+
+	cells map[image.Point][]*hashItem
+	items map[int]*hashItem
+	free  [][]*hashItem // Slices of emptied cells, reused by new cells
+	stamp uint64
+}
+
+type hashItem struct {
+	id    int
+	box   AABB
+	cells image.Rectangle // Covered cells, Max is exclusive
+	stamp uint64          // Last query that visited this item
+}
+
+// NewSpatialHash returns new SpatialHash
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	return &SpatialHash{
+		CellSize: cellSize,
+		cells:    make(map[image.Point][]*hashItem),
+		items:    make(map[int]*hashItem),
+	}
+}
+
+// Len returns the number of objects in the hash.
+func (s *SpatialHash) Len() int { return len(s.items) }
+
+// Insert adds the box with the given id. If the id already exists, it is moved instead.
+func (s *SpatialHash) Insert(id int, box *AABB) {
+	if _, ok := s.items[id]; ok {
+		s.Move(id, box)
+		return
+	}
+	item := &hashItem{id: id, box: *box, cells: s.cellRange(box.Min(), box.Max())}
+	s.items[id] = item
+	s.link(item)
+}
+
+// Move updates the box of id. It does nothing if the id does not exist.
+func (s *SpatialHash) Move(id int, box *AABB) {
+	item, ok := s.items[id]
+	if !ok {
+		return
+	}
+	item.box = *box
+	cells := s.cellRange(box.Min(), box.Max())
+	if cells == item.cells {
+		return
+	}
+	s.unlink(item)
+	item.cells = cells
+	s.link(item)
+}
+
+// Remove deletes id from the hash.
+func (s *SpatialHash) Remove(id int) {
+	item, ok := s.items[id]
+	if !ok {
+		return
+	}
+	s.unlink(item)
+	delete(s.items, id)
+}
+
+// QueryAABB appends the IDs of all boxes overlapping box to dst and returns the extended slice.
+func (s *SpatialHash) QueryAABB(box *AABB, dst []int) []int {
+	s.stamp++
+	cells := s.cellRange(box.Min(), box.Max())
+	for y := cells.Min.Y; y < cells.Max.Y; y++ {
+		for x := cells.Min.X; x < cells.Max.X; x++ {
+			for _, item := range s.cells[image.Point{x, y}] {
+				if item.stamp != s.stamp {
+					item.stamp = s.stamp
+					if BoxBoxOverlap(&item.box, box, nil) {
+						dst = append(dst, item.id)
+					}
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// QueryPoint appends the IDs of all boxes containing point to dst and returns the extended slice.
+func (s *SpatialHash) QueryPoint(point v.Vec, dst []int) []int {
+	cell := s.cellRange(point, point).Min
+	for _, item := range s.cells[cell] {
+		if BoxPointOverlap(&item.box, point, nil) {
+			dst = append(dst, item.id)
+		}
+	}
+	return dst
+}
+
+// QueryCircle appends the IDs of all boxes overlapping circle c to dst and returns the extended slice.
+func (s *SpatialHash) QueryCircle(c *Circle, dst []int) []int {
+	s.stamp++
+	rad := v.Vec{X: c.Radius, Y: c.Radius}
+	cells := s.cellRange(c.Pos.Sub(rad), c.Pos.Add(rad))
+	for y := cells.Min.Y; y < cells.Max.Y; y++ {
+		for x := cells.Min.X; x < cells.Max.X; x++ {
+			for _, item := range s.cells[image.Point{x, y}] {
+				if item.stamp != s.stamp {
+					item.stamp = s.stamp
+					if BoxCircleOverlap(&item.box, c, nil) {
+						dst = append(dst, item.id)
+					}
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// Pairs appends all overlapping ID pairs to dst and returns the extended slice.
+//
+// Each pair is reported once, with the smaller ID first. The order of the pairs is not defined.
+func (s *SpatialHash) Pairs(dst [][2]int) [][2]int {
+	for cell, items := range s.cells {
+		for i, a := range items {
+			for _, b := range items[i+1:] {
+				// report the pair only in the first cell shared by both boxes
+				first := image.Point{max(a.cells.Min.X, b.cells.Min.X), max(a.cells.Min.Y, b.cells.Min.Y)}
+				if first != cell || !BoxBoxOverlap(&a.box, &b.box, nil) {
+					continue
+				}
+				dst = append(dst, [2]int{min(a.id, b.id), max(a.id, b.id)})
+			}
+		}
+	}
+	return dst
+}
+
+// cellRange returns the cells covered by the bounds from minPos to maxPos
+func (s *SpatialHash) cellRange(minPos, maxPos v.Vec) image.Rectangle {
+	return image.Rectangle{
+		Min: image.Point{
+			X: int(math.Floor(minPos.X / s.CellSize)),
+			Y: int(math.Floor(minPos.Y / s.CellSize)),
+		},
+		Max: image.Point{
+			X: int(math.Floor(maxPos.X/s.CellSize)) + 1,
+			Y: int(math.Floor(maxPos.Y/s.CellSize)) + 1,
+		},
+	}
+}
+
+// link adds item to all of its cells
+func (s *SpatialHash) link(item *hashItem) {
+	for y := item.cells.Min.Y; y < item.cells.Max.Y; y++ {
+		for x := item.cells.Min.X; x < item.cells.Max.X; x++ {
+			cell := image.Point{x, y}
+			items, ok := s.cells[cell]
+			if !ok && len(s.free) > 0 {
+				items = s.free[len(s.free)-1]
+				s.free = s.free[:len(s.free)-1]
+			}
+			s.cells[cell] = append(items, item)
+		}
+	}
+}
+
+// unlink removes item from all of its cells. Emptied cells are deleted.
+func (s *SpatialHash) unlink(item *hashItem) {
+	for y := item.cells.Min.Y; y < item.cells.Max.Y; y++ {
+		for x := item.cells.Min.X; x < item.cells.Max.X; x++ {
+			cell := image.Point{x, y}
+			items := s.cells[cell]
+			for i, other := range items {
+				if other == item {
+					last := len(items) - 1
+					items[i] = items[last]
+					items[last] = nil
+					if last == 0 {
+						delete(s.cells, cell)
+						s.free = append(s.free, items[:0])
+					} else {
+						s.cells[cell] = items[:last]
+					}
+					break
+				}
+			}
+		}
+	}
+}
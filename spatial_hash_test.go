@@ -0,0 +1,92 @@
+package coll
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/setanarut/v"
+)
+
+func TestSpatialHashBruteForce(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	hash := NewSpatialHash(2)
+	boxes := map[int]AABB{}
+
+	check := func(step int) {
+		t.Helper()
+		if hash.Len() != len(boxes) {
+			t.Fatalf("step %d: Len = %d, want %d", step, hash.Len(), len(boxes))
+		}
+		got := sortedPairs(t, hash.Pairs(nil))
+		if want := bruteForcePairs(boxes); !slices.Equal(got, want) {
+			t.Fatalf("step %d: Pairs = %v, want %v", step, got, want)
+		}
+
+		query := randomBox(r)
+		circle := Circle{Pos: query.Pos, Radius: query.Half.X + 0.25}
+		point := query.Pos
+		var wantBox, wantCircle, wantPoint []int
+		for id, box := range boxes {
+			if BoxBoxOverlap(&box, &query, nil) {
+				wantBox = append(wantBox, id)
+			}
+			if BoxCircleOverlap(&box, &circle, nil) {
+				wantCircle = append(wantCircle, id)
+			}
+			if BoxPointOverlap(&box, point, nil) {
+				wantPoint = append(wantPoint, id)
+			}
+		}
+		for _, q := range []struct {
+			name      string
+			got, want []int
+		}{
+			{"QueryAABB", hash.QueryAABB(&query, nil), wantBox},
+			{"QueryCircle", hash.QueryCircle(&circle, nil), wantCircle},
+			{"QueryPoint", hash.QueryPoint(point, nil), wantPoint},
+		} {
+			slices.Sort(q.got)
+			slices.Sort(q.want)
+			if !slices.Equal(q.got, q.want) {
+				t.Fatalf("step %d: %s = %v, want %v", step, q.name, q.got, q.want)
+			}
+		}
+	}
+
+	for id := range 60 {
+		box := randomBox(r)
+		hash.Insert(id, &box)
+		boxes[id] = box
+	}
+	check(0)
+
+	for step := 1; step <= 20; step++ {
+		for id := range 80 {
+			switch r.IntN(6) {
+			case 0:
+				hash.Remove(id)
+				delete(boxes, id)
+			case 1:
+				// Insert moves existing IDs
+				box := randomBox(r)
+				hash.Insert(id, &box)
+				boxes[id] = box
+			case 2:
+				box := randomBox(r)
+				hash.Move(id, &box)
+				if _, ok := boxes[id]; ok {
+					boxes[id] = box
+				}
+			case 3:
+				// small moves mostly stay in the same cells
+				if box, ok := boxes[id]; ok {
+					box.Pos = box.Pos.Add(v.Vec{X: 0.5})
+					hash.Move(id, &box)
+					boxes[id] = box
+				}
+			}
+		}
+		check(step)
+	}
+}
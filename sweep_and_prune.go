@@ -0,0 +1,182 @@
+package coll
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// SweepAndPrune is an incremental sweep and prune broadphase on the X axis.
+//
+// It works best for worlds that are wider than they are tall, such as side-scrollers.
+// The endpoint list stays sorted between updates and is fixed with insertion sort, which is
+// fast when objects move only a little each frame.
+//
+// Each object can have a displacement. Its bounds are then extended to cover the whole movement,
+// and the reported pairs can be tested with BoxBoxSweep2().
+type SweepAndPrune struct {
+	Added   [][2]int // Pairs that started overlapping in the last Update(), smaller ID first
+	Removed [][2]int // Pairs that stopped overlapping in the last Update(), smaller ID first
+
+	items     map[int]*sapItem
+	endpoints []sapEndpoint
+	active    []*sapItem
+	pairs     [][2]int // Sorted pairs of the last update
+	prevPairs [][2]int
+	dirty     bool // Some items were removed
+}
+
+type sapItem struct {
+	id      int
+	min     v.Vec // Top-left corner of the swept bounds
+	max     v.Vec // Bottom-right corner of the swept bounds
+	removed bool
+}
+
+type sapEndpoint struct {
+	item  *sapItem
+	value float64
+	isMax bool
+}
+
+// NewSweepAndPrune returns new SweepAndPrune
+func NewSweepAndPrune() *SweepAndPrune {
+	return &SweepAndPrune{items: make(map[int]*sapItem)}
+}
+
+// Len returns the number of objects.
+func (s *SweepAndPrune) Len() int { return len(s.items) }
+
+// Insert adds the box with the given id and displacement. If the id already exists, it is moved instead.
+func (s *SweepAndPrune) Insert(id int, box *AABB, delta v.Vec) {
+	if _, ok := s.items[id]; ok {
+		s.Move(id, box, delta)
+		return
+	}
+	item := &sapItem{id: id}
+	item.setBounds(box, delta)
+	s.items[id] = item
+	s.endpoints = append(s.endpoints,
+		sapEndpoint{item: item, value: item.min.X},
+		sapEndpoint{item: item, value: item.max.X, isMax: true},
+	)
+}
+
+// Move updates the box and displacement of id. It does nothing if the id does not exist.
+// The change is applied in the next Update().
+func (s *SweepAndPrune) Move(id int, box *AABB, delta v.Vec) {
+	if item, ok := s.items[id]; ok {
+		item.setBounds(box, delta)
+	}
+}
+
+// Remove deletes id. Its pairs are reported in Removed by the next Update().
+func (s *SweepAndPrune) Remove(id int) {
+	if item, ok := s.items[id]; ok {
+		item.removed = true
+		delete(s.items, id)
+		s.dirty = true
+	}
+}
+
+// Pairs returns the overlapping pairs found by the last Update(), sorted and with the smaller ID first.
+// The slice is reused by the next Update().
+func (s *SweepAndPrune) Pairs() [][2]int { return s.pairs }
+
+// Update sorts the endpoints, finds the overlapping pairs and fills Added and Removed.
+func (s *SweepAndPrune) Update() {
+	if s.dirty {
+		s.endpoints = slices.DeleteFunc(s.endpoints, func(e sapEndpoint) bool { return e.item.removed })
+		s.dirty = false
+	}
+	for i := range s.endpoints {
+		e := &s.endpoints[i]
+		if e.isMax {
+			e.value = e.item.max.X
+		} else {
+			e.value = e.item.min.X
+		}
+	}
+
+	// insertion sort, nearly linear for coherent movement
+	for i := 1; i < len(s.endpoints); i++ {
+		e := s.endpoints[i]
+		j := i - 1
+		for j >= 0 && endpointLess(&e, &s.endpoints[j]) {
+			s.endpoints[j+1] = s.endpoints[j]
+			j--
+		}
+		s.endpoints[j+1] = e
+	}
+
+	// sweep
+	s.prevPairs, s.pairs = s.pairs, s.prevPairs[:0]
+	s.active = s.active[:0]
+	for _, e := range s.endpoints {
+		if e.isMax {
+			i := slices.Index(s.active, e.item)
+			if i < 0 {
+				continue
+			}
+			last := len(s.active) - 1
+			s.active[i] = s.active[last]
+			s.active[last] = nil
+			s.active = s.active[:last]
+			continue
+		}
+		for _, other := range s.active {
+			// the X checks reject touching bounds in any order of equal endpoints
+			if e.item.min.X < other.max.X && other.min.X < e.item.max.X &&
+				e.item.min.Y < other.max.Y && other.min.Y < e.item.max.Y {
+				s.pairs = append(s.pairs, [2]int{min(e.item.id, other.id), max(e.item.id, other.id)})
+			}
+		}
+		s.active = append(s.active, e.item)
+	}
+	slices.SortFunc(s.pairs, comparePairs)
+
+	// diff with the previous pairs
+	s.Added = s.Added[:0]
+	s.Removed = s.Removed[:0]
+	i, j := 0, 0
+	for i < len(s.pairs) || j < len(s.prevPairs) {
+		switch {
+		case j == len(s.prevPairs) || (i < len(s.pairs) && comparePairs(s.pairs[i], s.prevPairs[j]) < 0):
+			s.Added = append(s.Added, s.pairs[i])
+			i++
+		case i == len(s.pairs) || comparePairs(s.pairs[i], s.prevPairs[j]) > 0:
+			s.Removed = append(s.Removed, s.prevPairs[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+}
+
+// setBounds sets the bounds of the item to the box swept by delta
+func (item *sapItem) setBounds(box *AABB, delta v.Vec) {
+	lo, hi := box.Min(), box.Max()
+	item.min = v.Vec{X: min(lo.X, lo.X+delta.X), Y: min(lo.Y, lo.Y+delta.Y)}
+	item.max = v.Vec{X: max(hi.X, hi.X+delta.X), Y: max(hi.Y, hi.Y+delta.Y)}
+}
+
+// endpointLess orders endpoints by value. On ties, the min endpoint of an item comes before its own max
+// endpoint, and max endpoints of other items come first, so touching bounds don't overlap.
+func endpointLess(a, b *sapEndpoint) bool {
+	if a.value != b.value {
+		return a.value < b.value
+	}
+	if a.item == b.item {
+		return !a.isMax && b.isMax
+	}
+	return a.isMax && !b.isMax
+}
+
+func comparePairs(a, b [2]int) int {
+	if c := cmp.Compare(a[0], b[0]); c != 0 {
+		return c
+	}
+	return cmp.Compare(a[1], b[1])
+}
@@ -0,0 +1,78 @@
+package coll
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/setanarut/v"
+)
+
+func TestSweepAndPruneBruteForce(t *testing.T) {
+	r := rand.New(rand.NewPCG(5, 6))
+	sap := NewSweepAndPrune()
+	swept := map[int]AABB{} // boxes extended by their displacement
+	var prev [][2]int
+
+	set := func(id int) {
+		box := randomBox(r)
+		delta := v.Vec{}
+		if r.IntN(3) == 0 {
+			delta = v.Vec{X: float64(r.IntN(9)-4) / 2, Y: float64(r.IntN(9)-4) / 2}
+		}
+		sap.Insert(id, &box, delta)
+		lo, hi := box.Min(), box.Max()
+		swept[id] = boundsBox(
+			v.Vec{X: min(lo.X, lo.X+delta.X), Y: min(lo.Y, lo.Y+delta.Y)},
+			v.Vec{X: max(hi.X, hi.X+delta.X), Y: max(hi.Y, hi.Y+delta.Y)},
+		)
+	}
+
+	check := func(step int) {
+		t.Helper()
+		sap.Update()
+		if sap.Len() != len(swept) {
+			t.Fatalf("step %d: Len = %d, want %d", step, sap.Len(), len(swept))
+		}
+		want := bruteForcePairs(swept)
+		if got := sortedPairs(t, sap.Pairs()); !slices.Equal(got, want) {
+			t.Fatalf("step %d: Pairs = %v, want %v", step, got, want)
+		}
+		var wantAdded, wantRemoved [][2]int
+		for _, p := range want {
+			if !slices.Contains(prev, p) {
+				wantAdded = append(wantAdded, p)
+			}
+		}
+		for _, p := range prev {
+			if !slices.Contains(want, p) {
+				wantRemoved = append(wantRemoved, p)
+			}
+		}
+		if got := sortedPairs(t, sap.Added); !slices.Equal(got, wantAdded) {
+			t.Fatalf("step %d: Added = %v, want %v", step, got, wantAdded)
+		}
+		if got := sortedPairs(t, sap.Removed); !slices.Equal(got, wantRemoved) {
+			t.Fatalf("step %d: Removed = %v, want %v", step, got, wantRemoved)
+		}
+		prev = want
+	}
+
+	for id := range 60 {
+		set(id)
+	}
+	check(0)
+
+	for step := 1; step <= 30; step++ {
+		for id := range 80 {
+			switch r.IntN(8) {
+			case 0:
+				sap.Remove(id)
+				delete(swept, id)
+			case 1, 2:
+				set(id)
+			}
+		}
+		check(step)
+	}
+}
@@ -0,0 +1,116 @@
+package coll
+
+import (
+	"image"
+
+	"github.com/setanarut/v"
+)
+
+// TilemapOutline traces the boundary between solid and empty tiles and returns it as closed loops of segments.
+//
+// Collinear edges are merged. The segments of each loop are connected end to end and
+// their normals (see SegmentNormal) point out of the solid region, so the result can be used
+// directly with BoxSegmentSweep1() and BoxSegmentsSweep1Indexed().
+//
+// Cells outside the tilemap are treated as empty. Tiles touching only at a corner are not connected.
+func TilemapOutline(tileMap [][]Tile, tileWidth, tileHeight int) [][]*Segment {
+	type edge struct {
+		from, to image.Point
+		used     bool
+	}
+
+	solid := func(x, y int) bool {
+		if y < 0 || y >= len(tileMap) || x < 0 || x >= len(tileMap[y]) {
+			return false
+		}
+		return tileMap[y][x] != nil && tileMap[y][x].IsSolid()
+	}
+
+	// Collect unit edges between solid and empty cells.
+	// The solid cell is always on the right side of the edge direction.
+	var edges []edge
+	outgoing := make(map[image.Point][]int)
+	addEdge := func(from, to image.Point) {
+		outgoing[from] = append(outgoing[from], len(edges))
+		edges = append(edges, edge{from: from, to: to})
+	}
+	for y := range tileMap {
+		for x := range tileMap[y] {
+			if !solid(x, y) {
+				continue
+			}
+			if !solid(x, y-1) {
+				addEdge(image.Point{x, y}, image.Point{x + 1, y})
+			}
+			if !solid(x+1, y) {
+				addEdge(image.Point{x + 1, y}, image.Point{x + 1, y + 1})
+			}
+			if !solid(x, y+1) {
+				addEdge(image.Point{x + 1, y + 1}, image.Point{x, y + 1})
+			}
+			if !solid(x-1, y) {
+				addEdge(image.Point{x, y + 1}, image.Point{x, y})
+			}
+		}
+	}
+
+	scale := func(p image.Point) v.Vec {
+		return v.Vec{X: float64(p.X * tileWidth), Y: float64(p.Y * tileHeight)}
+	}
+
+	var loops [][]*Segment
+	var chain []int
+	for first := range edges {
+		if edges[first].used {
+			continue
+		}
+
+		// Walk the loop. At a vertex shared by two loops (tiles touching at a corner)
+		// prefer turning right, towards the solid cell, to keep the loops apart.
+		chain = chain[:0]
+		for cur := first; cur >= 0 && !edges[cur].used; {
+			edges[cur].used = true
+			chain = append(chain, cur)
+			dir := edges[cur].to.Sub(edges[cur].from)
+			right := image.Point{-dir.Y, dir.X}
+			next := -1
+			for _, e := range outgoing[edges[cur].to] {
+				if edges[e].used && e != first {
+					continue
+				}
+				d := edges[e].to.Sub(edges[e].from)
+				if next < 0 || d == right {
+					next = e
+				}
+			}
+			cur = next
+		}
+
+		// Start the loop at a corner so that no merged segment wraps around the end.
+		dirOf := func(i int) image.Point {
+			e := edges[chain[i%len(chain)]]
+			return e.to.Sub(e.from)
+		}
+		start := 0
+		for i := range chain {
+			if dirOf(i) != dirOf(i+len(chain)-1) {
+				start = i
+				break
+			}
+		}
+
+		var loop []*Segment
+		for i := start; i < start+len(chain); {
+			j := i + 1
+			for j < start+len(chain) && dirOf(j) == dirOf(i) {
+				j++
+			}
+			from := edges[chain[i%len(chain)]].from
+			to := edges[chain[(j-1)%len(chain)]].to
+			loop = append(loop, &Segment{A: scale(from), B: scale(to)})
+			i = j
+		}
+		loops = append(loops, loop)
+	}
+	return loops
+}
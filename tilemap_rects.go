@@ -0,0 +1,160 @@
+package coll
+
+import (
+	"image"
+	"slices"
+
+	"github.com/setanarut/v"
+)
+
+// TileRects covers the solid tiles of a tilemap with a near-minimal set of boxes.
+//
+// Solid cells are merged greedily, first along rows and then down the columns.
+// The boxes can be used with BoxBoxSweep1() loops or inserted into a broadphase
+// instead of the individual tiles.
+type TileRects struct {
+	Rects    []AABB            // Boxes covering all solid tiles
+	Cells    []image.Rectangle // Tile coordinates covered by each box in Rects
+	CellSize image.Point       // Width and height of tiles
+	TileMap  [][]Tile          // 2D grid of tile interface
+
+	owner [][]int // Index in Rects of the box covering each cell, -1 if none
+}
+
+// NewTileRects creates a new TileRects and merges the solid tiles of tileMap
+func NewTileRects(tileMap [][]Tile, tileWidth, tileHeight int) *TileRects {
+	t := &TileRects{
+		TileMap:  tileMap,
+		CellSize: image.Point{tileWidth, tileHeight},
+	}
+	t.Rebuild()
+	return t
+}
+
+// MergeTiles returns a near-minimal set of boxes covering all solid tiles of tileMap.
+func MergeTiles(tileMap [][]Tile, tileWidth, tileHeight int) []AABB {
+	return NewTileRects(tileMap, tileWidth, tileHeight).Rects
+}
+
+// Rebuild merges the whole tilemap from scratch.
+func (t *TileRects) Rebuild() {
+	t.Rects = t.Rects[:0]
+	t.Cells = t.Cells[:0]
+	t.owner = make([][]int, len(t.TileMap))
+
+	width := 0
+	for y := range t.TileMap {
+		t.owner[y] = make([]int, len(t.TileMap[y]))
+		for x := range t.owner[y] {
+			t.owner[y][x] = -1
+		}
+		width = max(width, len(t.TileMap[y]))
+	}
+	t.merge(image.Rect(0, 0, width, len(t.TileMap)))
+}
+
+// Update re-merges the area around the tile at x, y after it has changed.
+//
+// Only the boxes covering the tile and its 4 neighbors are merged again, so the result
+// may drift away from the minimal set over many updates. Call Rebuild() to restore it.
+func (t *TileRects) Update(x, y int) {
+	if y < 0 || y >= len(t.owner) || x < 0 || x >= len(t.owner[y]) {
+		return
+	}
+	region := image.Rect(x, y, x+1, y+1)
+	var boxes [5]int
+	n := 0
+	for _, p := range [5]image.Point{{x, y}, {x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+		if p.Y < 0 || p.Y >= len(t.owner) || p.X < 0 || p.X >= len(t.owner[p.Y]) {
+			continue
+		}
+		if i := t.owner[p.Y][p.X]; i >= 0 && !slices.Contains(boxes[:n], i) {
+			boxes[n] = i
+			n++
+			region = region.Union(t.Cells[i])
+		}
+	}
+	// remove from the highest index so the swapped boxes are never in the list
+	slices.Sort(boxes[:n])
+	for _, i := range slices.Backward(boxes[:n]) {
+		t.remove(i)
+	}
+	t.merge(region)
+}
+
+// merge covers the unowned solid cells inside region with new boxes
+func (t *TileRects) merge(region image.Rectangle) {
+	for y := region.Min.Y; y < region.Max.Y; y++ {
+		for x := region.Min.X; x < region.Max.X; x++ {
+			if !t.free(x, y) {
+				continue
+			}
+			w := 1
+			for t.free(x+w, y) {
+				w++
+			}
+			h := 1
+			for t.rowFree(x, x+w, y+h) {
+				h++
+			}
+			t.add(image.Rect(x, y, x+w, y+h))
+		}
+	}
+}
+
+// free returns true if the cell is solid and not covered by a box
+func (t *TileRects) free(x, y int) bool {
+	if y < 0 || y >= len(t.owner) || x < 0 || x >= len(t.owner[y]) || x >= len(t.TileMap[y]) {
+		return false
+	}
+	tile := t.TileMap[y][x]
+	return tile != nil && tile.IsSolid() && t.owner[y][x] < 0
+}
+
+// rowFree returns true if all cells from x0 to x1 (exclusive) in row y are free
+func (t *TileRects) rowFree(x0, x1, y int) bool {
+	for x := x0; x < x1; x++ {
+		if !t.free(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// add appends a box covering cells
+func (t *TileRects) add(cells image.Rectangle) {
+	t.setOwner(cells, len(t.Rects))
+	t.Cells = append(t.Cells, cells)
+	t.Rects = append(t.Rects, AABB{
+		Pos: v.Vec{
+			X: float64((cells.Min.X+cells.Max.X)*t.CellSize.X) / 2,
+			Y: float64((cells.Min.Y+cells.Max.Y)*t.CellSize.Y) / 2,
+		},
+		Half: v.Vec{
+			X: float64(cells.Dx()*t.CellSize.X) / 2,
+			Y: float64(cells.Dy()*t.CellSize.Y) / 2,
+		},
+	})
+}
+
+// remove deletes the box at index i by moving the last box into its place
+func (t *TileRects) remove(i int) {
+	t.setOwner(t.Cells[i], -1)
+	last := len(t.Rects) - 1
+	if i != last {
+		t.Rects[i] = t.Rects[last]
+		t.Cells[i] = t.Cells[last]
+		t.setOwner(t.Cells[i], i)
+	}
+	t.Rects = t.Rects[:last]
+	t.Cells = t.Cells[:last]
+}
+
+// setOwner marks all cells as covered by the box at index i
+func (t *TileRects) setOwner(cells image.Rectangle, i int) {
+	for y := cells.Min.Y; y < cells.Max.Y; y++ {
+		for x := cells.Min.X; x < cells.Max.X; x++ {
+			t.owner[y][x] = i
+		}
+	}
+}
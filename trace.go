@@ -0,0 +1,108 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// Trace is the result of moving a shape along a straight path until it hits a solid,
+// like the traces of classic engines.
+//
+// A shape that starts inside a solid is not blocked by it, unless it can't leave the solid
+// during the movement (AllSolid). Touching is not overlapping, so a shape resting against
+// a solid and moving into it hits it at Fraction 0 without StartSolid.
+type Trace struct {
+	Fraction   float64 // Fraction of the movement (0.0 to 1.0) completed before the hit, 1.0 if nothing was hit
+	EndPos     v.Vec   // Position of the moving shape at Fraction
+	Normal     v.Vec   // Surface normal of the hit for the moving shape, zero if nothing was hit
+	StartSolid bool    // The start position is inside a solid
+	AllSolid   bool    // The whole movement is inside a solid, Fraction is 0
+}
+
+// BoxBoxTrace traces box b moving by deltaB against the static box a.
+func BoxBoxTrace(a, b *AABB, deltaB v.Vec) Trace {
+	var iv Interval
+	ok := BoxBoxSweep1Interval(a, b, deltaB, &iv)
+	return newTrace(b.Pos, deltaB, &iv, ok, func(pos v.Vec) v.Vec {
+		return boxBoxNormal(a, &AABB{Pos: pos, Half: b.Half})
+	})
+}
+
+// BoxCircleTrace traces box a moving by deltaA against the static circle c.
+func BoxCircleTrace(a *AABB, c *Circle, deltaA v.Vec) Trace {
+	var iv Interval
+	ok := BoxCircleSweep2Interval(a, c, deltaA, v.Vec{}, &iv)
+	return newTrace(a.Pos, deltaA, &iv, ok, func(pos v.Vec) v.Vec {
+		return boxPointNormal(&AABB{Pos: pos, Half: a.Half}, c.Pos).Neg()
+	})
+}
+
+// CircleBoxTrace traces circle c moving by deltaC against the static box a.
+func CircleBoxTrace(c *Circle, a *AABB, deltaC v.Vec) Trace {
+	var iv Interval
+	ok := BoxCircleSweep2Interval(a, c, v.Vec{}, deltaC, &iv)
+	return newTrace(c.Pos, deltaC, &iv, ok, func(pos v.Vec) v.Vec {
+		return boxPointNormal(a, pos)
+	})
+}
+
+// CircleCircleTrace traces circle c1 moving by deltaC1 against the static circle c2.
+func CircleCircleTrace(c1, c2 *Circle, deltaC1 v.Vec) Trace {
+	var iv Interval
+	ok := CircleCircleSweep2Interval(c1, c2, deltaC1, v.Vec{}, &iv)
+	return newTrace(c1.Pos, deltaC1, &iv, ok, func(pos v.Vec) v.Vec {
+		return pos.Sub(c2.Pos).Unit()
+	})
+}
+
+// newTrace returns the Trace of a shape at start moving by delta against a solid that it overlaps
+// in the time interval iv (ok is false if it doesn't overlap). normal returns the surface normal
+// for the moving shape at the given position.
+func newTrace(start, delta v.Vec, iv *Interval, ok bool, normal func(pos v.Vec) v.Vec) Trace {
+	tr := Trace{Fraction: 1, EndPos: start.Add(delta)}
+	if !ok {
+		return tr
+	}
+	if iv.StartOverlapping {
+		tr.StartSolid = true
+		if iv.Exit >= 1 {
+			tr.AllSolid = true
+			tr.Fraction = 0
+			tr.EndPos = start
+		}
+		return tr
+	}
+	tr.Fraction = max(iv.Enter-Epsilon, 0)
+	tr.EndPos = start.Add(delta.Scale(tr.Fraction))
+	tr.Normal = normal(start.Add(delta.Scale(iv.Enter)))
+	return tr
+}
+
+// boxPointNormal returns the direction from box a to point p.
+// If p is inside the box, the normal of the closest edge is returned.
+func boxPointNormal(a *AABB, p v.Vec) v.Vec {
+	d := p.Sub(a.Pos)
+	clamped := v.Vec{
+		X: max(-a.Half.X, min(d.X, a.Half.X)),
+		Y: max(-a.Half.Y, min(d.Y, a.Half.Y)),
+	}
+	if n := d.Sub(clamped); !n.IsZero() {
+		return n.Unit()
+	}
+	if a.Half.X-math.Abs(d.X) < a.Half.Y-math.Abs(d.Y) {
+		return v.Vec{X: math.Copysign(1, d.X)}
+	}
+	return v.Vec{Y: math.Copysign(1, d.Y)}
+}
+
+// boxBoxNormal returns the surface normal for box b touching box a,
+// along the axis with the smaller overlap.
+func boxBoxNormal(a, b *AABB) v.Vec {
+	d := b.Pos.Sub(a.Pos)
+	hSum := a.Half.Add(b.Half)
+	if math.Abs(d.X)-hSum.X > math.Abs(d.Y)-hSum.Y {
+		return v.Vec{X: math.Copysign(1, d.X)}
+	}
+	return v.Vec{Y: math.Copysign(1, d.Y)}
+}
@@ -0,0 +1,348 @@
+package coll
+
+import (
+	"math"
+
+	"github.com/setanarut/v"
+)
+
+// Body is a shape registered in a World.
+type Body struct {
+	// Shape is one of *AABB, *Circle, *OBB or *Segment.
+	// Call World.Update() after changing it.
+	Shape any
+	// Category bits of the body.
+	Category uint32
+	// Mask bits of the categories this body collides with.
+	Mask uint32
+	// Bodies in the same positive group always collide,
+	// bodies in the same negative group never collide. Zero means no group.
+	Group int32
+	// Sensor bodies are reported by Overlaps() but ignored by Sweep() and Raycast().
+	Sensor bool
+	// UserData is not used by the World.
+	UserData any
+
+	proxy int
+	world *World
+}
+
+// NewBody returns new Body in category 1 that collides with all categories.
+func NewBody(shape any, userData any) *Body {
+	return &Body{Shape: shape, Category: 1, Mask: math.MaxUint32, UserData: userData, proxy: nullNode}
+}
+
+// ShouldCollide returns true if the group, category and mask bits of a and b let them collide.
+func (a *Body) ShouldCollide(b *Body) bool {
+	if a.Group == b.Group && a.Group != 0 {
+		return a.Group > 0
+	}
+	return a.Mask&b.Category != 0 && b.Mask&a.Category != 0
+}
+
+// World keeps bodies in an AABBTree broadphase and runs filtered queries on them
+// with the pair functions of this package.
+type World struct {
+	tree   *AABBTree
+	bodies map[int]*Body // Bodies by proxy ID
+}
+
+// NewWorld returns new World. margin is the fat box margin of the broadphase (see AABBTree).
+func NewWorld(margin float64) *World {
+	return &World{tree: NewAABBTree(margin), bodies: make(map[int]*Body)}
+}
+
+// Len returns the number of bodies in the world.
+func (w *World) Len() int { return len(w.bodies) }
+
+// Add registers b in the world. It does nothing if b is already in a world.
+func (w *World) Add(b *Body) {
+	if b.world != nil {
+		return
+	}
+	box := shapeBounds(b.Shape)
+	b.proxy = w.tree.CreateProxy(&box, 0)
+	b.world = w
+	w.bodies[b.proxy] = b
+}
+
+// Remove deletes b from the world.
+func (w *World) Remove(b *Body) {
+	if b.world != w {
+		return
+	}
+	w.tree.DestroyProxy(b.proxy)
+	delete(w.bodies, b.proxy)
+	b.world = nil
+	b.proxy = nullNode
+}
+
+// Update refreshes the broadphase after the shape of b has changed.
+// displacement is the expected movement of the body, used to enlarge its fat box.
+func (w *World) Update(b *Body, displacement v.Vec) {
+	if b.world != w {
+		return
+	}
+	box := shapeBounds(b.Shape)
+	w.tree.MoveProxy(b.proxy, &box, displacement)
+}
+
+// Overlaps appends the bodies overlapping b that pass the collision filter to dst
+// and returns the extended slice. Sensor bodies are included.
+//
+// Touching is not overlapping: shapes that only share boundary points are not reported.
+// Segments have no interior, so two segments overlap if they share any point.
+func (w *World) Overlaps(b *Body, dst []*Body) []*Body {
+	box := shapeBounds(b.Shape)
+	w.tree.QueryAABB(&box, func(proxy int) bool {
+		other := w.bodies[proxy]
+		if other != b && b.ShouldCollide(other) && shapesOverlap(b.Shape, other.Shape) {
+			dst = append(dst, other)
+		}
+		return true
+	})
+	return dst
+}
+
+// Sweep moves the shape of b by delta against the non-sensor bodies that pass the collision filter
+// and returns the body hit first, or nil. b itself is not moved.
+//
+// Moving *AABB shapes are tested against *AABB, *Circle and *Segment shapes,
+// moving *Circle shapes against *AABB, *Circle and *Segment shapes. Other shape pairs are ignored.
+// Segments only block from their front side (see BoxSegmentSweep1 and CircleSegmentSweep1).
+//
+// If h is not nil and a collision is detected, it will be populated with:
+//   - Normal: Collision surface normal for b
+//   - Data: Normalized time of impact (0.0 to 1.0) along the movement path
+func (w *World) Sweep(b *Body, delta v.Vec, h *Hit) *Body {
+	var result *Body
+	var resHit, tmpHit Hit
+
+	box := shapeBounds(b.Shape)
+	moved := AABB{Pos: box.Pos.Add(delta), Half: box.Half}
+	swept := unionBox(&box, &moved)
+
+	w.tree.QueryAABB(&swept, func(proxy int) bool {
+		other := w.bodies[proxy]
+		if other == b || other.Sensor || !b.ShouldCollide(other) || !shapeSweep(b.Shape, other.Shape, delta, &tmpHit) {
+			return true
+		}
+		if result == nil || tmpHit.Data < resHit.Data {
+			result = other
+			resHit = tmpHit
+		}
+		return true
+	})
+	if result != nil && h != nil {
+		*h = resHit
+	}
+	return result
+}
+
+// Raycast casts the ray from start to start+delta and returns the closest non-sensor body hit
+// whose category bits match mask, or nil. Segments are two-sided for rays.
+//
+// If h is not nil and a collision is detected, it will be populated with:
+//   - Normal: Surface normal of the body, facing the ray origin
+//   - Data: Normalized time of impact (0.0 to 1.0) along delta
+func (w *World) Raycast(start, delta v.Vec, mask uint32, h *Hit) *Body {
+	var result *Body
+	var resHit, tmpHit Hit
+
+	w.tree.RayCast(start, delta, func(proxy int, maxFraction float64) float64 {
+		other := w.bodies[proxy]
+		if other.Sensor || other.Category&mask == 0 || !shapeRaycast(other.Shape, start, delta, &tmpHit) || tmpHit.Data > maxFraction {
+			return maxFraction
+		}
+		result = other
+		resHit = tmpHit
+		return tmpHit.Data
+	})
+	if result != nil && h != nil {
+		*h = resHit
+	}
+	return result
+}
+
+// shapeBounds returns the bounding box of a *AABB, *Circle, *OBB or *Segment shape
+func shapeBounds(shape any) AABB {
+	switch s := shape.(type) {
+	case *AABB:
+		return *s
+	case *Circle:
+		return AABB{Pos: s.Pos, Half: v.Vec{X: s.Radius, Y: s.Radius}}
+	case *OBB:
+		axis := v.FromAngle(s.Angle).Abs()
+		return AABB{Pos: s.Pos, Half: v.Vec{
+			X: axis.X*s.Half.X + axis.Y*s.Half.Y,
+			Y: axis.Y*s.Half.X + axis.X*s.Half.Y,
+		}}
+	case *Segment:
+		return segmentBox(s)
+	}
+	return AABB{}
+}
+
+// shapesOverlap tests two *AABB, *Circle, *OBB or *Segment shapes for overlap.
+// Touching is not overlapping, except for two segments.
+func shapesOverlap(a, b any) bool {
+	if shapeRank(a) > shapeRank(b) {
+		a, b = b, a
+	}
+	switch sa := a.(type) {
+	case *AABB:
+		switch sb := b.(type) {
+		case *AABB:
+			return BoxBoxOverlap(sa, sb, nil)
+		case *Circle:
+			return ClosestPointOnBox(sa, sb.Pos).DistSq(sb.Pos) < sb.Radius*sb.Radius
+		case *OBB:
+			return orientedBoxesOverlap(&OBB{Pos: sa.Pos, Half: sa.Half}, sb)
+		case *Segment:
+			return BoxSegmentStaticOverlap(sa, sb, false, nil)
+		}
+	case *Circle:
+		switch sb := b.(type) {
+		case *Circle:
+			rSum := sa.Radius + sb.Radius
+			return sa.Pos.DistSq(sb.Pos) < rSum*rSum
+		case *OBB:
+			local := sa.Pos.Sub(sb.Pos).Rotate(-sb.Angle)
+			return ClosestPointOnBox(&AABB{Half: sb.Half}, local).DistSq(local) < sa.Radius*sa.Radius
+		case *Segment:
+			return segmentPointDistSq(sb, sa.Pos) < sa.Radius*sa.Radius
+		}
+	case *OBB:
+		switch sb := b.(type) {
+		case *OBB:
+			return orientedBoxesOverlap(sa, sb)
+		case *Segment:
+			local := Segment{A: sb.A.Sub(sa.Pos).Rotate(-sa.Angle), B: sb.B.Sub(sa.Pos).Rotate(-sa.Angle)}
+			return BoxSegmentStaticOverlap(&AABB{Half: sa.Half}, &local, false, nil)
+		}
+	case *Segment:
+		if sb, ok := b.(*Segment); ok {
+			return SegmentSegmentOverlap(sa, sb, nil)
+		}
+	}
+	return false
+}
+
+// shapeRank orders the shape types so that shapesOverlap only handles one order of each pair
+func shapeRank(shape any) int {
+	switch shape.(type) {
+	case *AABB:
+		return 0
+	case *Circle:
+		return 1
+	case *OBB:
+		return 2
+	}
+	return 3
+}
+
+// shapeSweep sweeps the moving shape a by delta against the static shape b.
+// The hit normal is for a.
+func shapeSweep(a, b any, delta v.Vec, h *Hit) bool {
+	switch sa := a.(type) {
+	case *AABB:
+		switch sb := b.(type) {
+		case *AABB:
+			if !BoxBoxSweep1(sb, sa, delta, h) {
+				return false
+			}
+			// without movement BoxBoxSweep1 fills the penetration depth, the time of impact is 0
+			if delta.IsZero() {
+				h.Data = 0
+			}
+			return true
+		case *Circle:
+			if !BoxCircleSweep2(sa, sb, delta, v.Vec{}, h) {
+				return false
+			}
+			// BoxCircleSweep2 fills the normal for the circle
+			h.Normal = h.Normal.Neg()
+			return true
+		case *Segment:
+			return BoxSegmentSweep1(sb, sa, delta, h)
+		}
+	case *Circle:
+		switch sb := b.(type) {
+		case *AABB:
+			return BoxCircleSweep2(sb, sa, v.Vec{}, delta, h)
+		case *Circle:
+			return CircleCircleSweep2(sa, sb, delta, v.Vec{}, h)
+		case *Segment:
+			return CircleSegmentSweep1(sb, sa, delta, h)
+		}
+	}
+	return false
+}
+
+// shapeRaycast casts the ray from start to start+delta against a *AABB, *Circle, *OBB or *Segment shape.
+// The hit normal faces the ray origin.
+func shapeRaycast(shape any, start, delta v.Vec, h *Hit) bool {
+	switch s := shape.(type) {
+	case *AABB:
+		return BoxSegmentOverlap(s, start, delta, v.Vec{}, h)
+	case *Circle:
+		return rayCircle(start, delta, s, h)
+	case *OBB:
+		localStart := start.Sub(s.Pos).Rotate(-s.Angle)
+		localDelta := delta.Rotate(-s.Angle)
+		if !BoxSegmentOverlap(&AABB{Half: s.Half}, localStart, localDelta, v.Vec{}, h) {
+			return false
+		}
+		h.Normal = h.Normal.Rotate(s.Angle)
+		return true
+	case *Segment:
+		return raySegment(start, delta, s, h)
+	}
+	return false
+}
+
+// rayCircle intersects the ray from start to start+delta with circle c.
+// A ray starting inside the circle hits it at time 0.
+func rayCircle(start, delta v.Vec, c *Circle, h *Hit) bool {
+	m := start.Sub(c.Pos)
+	cc := m.MagSq() - c.Radius*c.Radius
+	if cc <= 0 {
+		h.Data = 0
+		h.Normal = m.Unit()
+		return true
+	}
+	a := delta.MagSq()
+	b := m.Dot(delta)
+	disc := b*b - a*cc
+	if a < Epsilon || b > 0 || disc < 0 {
+		return false
+	}
+	t := (-b - math.Sqrt(disc)) / a
+	if t > 1 {
+		return false
+	}
+	h.Data = t
+	h.Normal = m.Add(delta.Scale(t)).Unit()
+	return true
+}
+
+// orientedBoxesOverlap tests two oriented boxes with the separating axis theorem. Touching is not overlapping.
+func orientedBoxesOverlap(a, b *OBB) bool {
+	d := b.Pos.Sub(a.Pos)
+	axes := [4]v.Vec{v.FromAngle(a.Angle), {}, v.FromAngle(b.Angle), {}}
+	axes[1] = v.Vec{X: -axes[0].Y, Y: axes[0].X}
+	axes[3] = v.Vec{X: -axes[2].Y, Y: axes[2].X}
+	for _, axis := range axes {
+		ra := a.Half.X*math.Abs(axes[0].Dot(axis)) + a.Half.Y*math.Abs(axes[1].Dot(axis))
+		rb := b.Half.X*math.Abs(axes[2].Dot(axis)) + b.Half.Y*math.Abs(axes[3].Dot(axis))
+		if math.Abs(d.Dot(axis)) >= ra+rb {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentPointDistSq returns the squared distance from p to the closest point of s
+func segmentPointDistSq(s *Segment, p v.Vec) float64 {
+	return p.DistSq(ClosestPointOnSegment(s, p))
+}
@@ -0,0 +1,23 @@
+package coll
+
+import (
+	"testing"
+
+	"github.com/setanarut/v"
+)
+
+func TestWorldSweepReportsTimes(t *testing.T) {
+	w := NewWorld(0)
+	mover := NewBody(NewAABB(0, 0, 1, 1), nil)
+	overlapping := NewBody(NewAABB(1.5, 0, 1, 1), nil)
+	w.Add(mover)
+	w.Add(overlapping)
+
+	var h Hit
+	if got := w.Sweep(mover, v.Vec{}, &h); got == nil {
+		t.Fatal("Sweep found no body")
+	}
+	if h.Data != 0 {
+		t.Errorf("Data = %v, want time 0 for a start overlap", h.Data)
+	}
+}